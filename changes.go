@@ -61,8 +61,17 @@ type AbandonInput struct {
 // ApprovalInfo entity contains information about an approval from a user for a label on a change.
 type ApprovalInfo struct {
 	AccountInfo
-	Value int    `json:"value,omitempty"`
-	Date  string `json:"date,omitempty"`
+	Value                int              `json:"value,omitempty"`
+	PermittedVotingRange *VotingRangeInfo `json:"permitted_voting_range,omitempty"`
+	Date                 Timestamp        `json:"date,omitempty"`
+	Tag                  string           `json:"tag,omitempty"`
+	PostSubmit           bool             `json:"post_submit,omitempty"`
+}
+
+// VotingRangeInfo entity describes the continuous voting range from min to max values.
+type VotingRangeInfo struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
 }
 
 // CommitMessageInput entity contains information for changing the commit message of a change.
@@ -77,6 +86,20 @@ type ReadyForReviewInput struct {
 	Message string `json:"message,omitempty"`
 }
 
+// WorkInProgressInput entity contains additional information for a change set to WorkInProgress.
+//
+// Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#work-in-progress-input
+type WorkInProgressInput struct {
+	Message string `json:"message,omitempty"`
+}
+
+// PrivateInput entity contains information for changing the private flag on a change.
+//
+// Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#private-input
+type PrivateInput struct {
+	Message string `json:"message,omitempty"`
+}
+
 // ChangeEditInput entity contains information for restoring a path within change edit.
 type ChangeEditInput struct {
 	RestorePath string `json:"restore_path,omitempty"`
@@ -93,6 +116,7 @@ type ChangeEditMessageInput struct {
 type ChangeMessageInfo struct {
 	ID             string      `json:"id"`
 	Author         AccountInfo `json:"author,omitempty"`
+	RealAuthor     AccountInfo `json:"real_author,omitempty"`
 	Date           Timestamp   `json:"date"`
 	Message        string      `json:"message"`
 	Tag            string      `json:"tag,omitempty"`
@@ -191,7 +215,22 @@ type RestoreInput struct {
 
 // RevertInput entity contains information for reverting a change.
 type RevertInput struct {
-	Message string `json:"message,omitempty"`
+	Message        string                       `json:"message,omitempty"`
+	Notify         string                       `json:"notify,omitempty"`
+	NotifyDetails  map[RecipientType]NotifyInfo `json:"notify_details,omitempty"`
+	Topic          string                       `json:"topic,omitempty"`
+	WorkInProgress bool                         `json:"work_in_progress,omitempty"`
+}
+
+// RevertSubmissionInfo entity describes the revert changes created by reverting a submission.
+type RevertSubmissionInfo struct {
+	RevertChanges []ChangeInfo `json:"revert_changes"`
+}
+
+// RebaseChainInfo entity contains information about a chain of changes that were rebased.
+type RebaseChainInfo struct {
+	RebasedChanges       []ChangeInfo `json:"rebased_changes"`
+	ContainsGitConflicts bool         `json:"contains_git_conflicts,omitempty"`
 }
 
 // ReviewInfo entity contains information about a review.
@@ -341,7 +380,7 @@ type RobotCommentInput struct {
 	Properties *map[string]*string `json:"properties,omitempty"`
 	// Suggested fixes for this robot comment as a list of FixSuggestionInfo
 	// entities.
-	FixSuggestions *FixSuggestionInfo `json:"fix_suggestions,omitempty"`
+	FixSuggestions []FixSuggestionInfo `json:"fix_suggestions,omitempty"`
 }
 
 // RobotCommentInfo entity contains information about a robot inline comment
@@ -360,7 +399,7 @@ type RobotCommentInfo struct {
 	Properties map[string]string `json:"properties,omitempty"`
 	// Suggested fixes for this robot comment as a list of FixSuggestionInfo
 	// entities.
-	FixSuggestions *FixSuggestionInfo `json:"fix_suggestions,omitempty"`
+	FixSuggestions []FixSuggestionInfo `json:"fix_suggestions,omitempty"`
 }
 
 // FixSuggestionInfo entity represents a suggested fix.
@@ -368,13 +407,13 @@ type RobotCommentInfo struct {
 type FixSuggestionInfo struct {
 	// The UUID of the suggested fix. It will be generated automatically and
 	// hence will be ignored if it’s set for input objects.
-	FixID string `json:"fix_id"`
+	FixID string `json:"fix_id,omitempty"`
 	// A description of the suggested fix.
 	Description string `json:"description"`
 	// A list of FixReplacementInfo entities indicating how the content of one or
 	// several files should be modified. Within a file, they should refer to
 	// non-overlapping regions.
-	Replacements FixReplacementInfo `json:"replacements"`
+	Replacements []FixReplacementInfo `json:"replacements"`
 }
 
 // FixReplacementInfo entity describes how the content of a file should be replaced by another content.
@@ -488,6 +527,8 @@ type ChangeInfo struct {
 	ContainsGitConflicts   bool                          `json:"contains_git_conflicts,omitempty"`
 	BaseChange             string                        `json:"base_change,omitempty"`
 	SubmitRequirements     []SubmitRequirementResultInfo `json:"submit_requirements,omitempty"`
+	MetaRevID              string                        `json:"meta_rev_id,omitempty"`
+	CustomKeyedValues      map[string]string             `json:"custom_keyed_values,omitempty"`
 }
 
 // LabelInfo entity contains information about a label on a change, always corresponding to the current patch set.
@@ -544,6 +585,7 @@ type RevisionInfo struct {
 	Reviewed          bool                  `json:"reviewed,omitempty"`
 	MessageWithFooter string                `json:"messageWithFooter,omitempty"`
 	ParentsData       []ParentInfo          `json:"parents_data,omitempty"`
+	Description       string                `json:"description,omitempty"`
 }
 
 // CommentInfo entity contains information about an inline comment.
@@ -628,6 +670,11 @@ type ChangeOptions struct {
 	//
 	// Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#list-changes
 	AdditionalFields []string `url:"o,omitempty"`
+
+	// Meta is the SHA-1 of a NoteDb meta revision of the change.
+	// If set, the change is returned as it was at that revision.
+	// It is only honored by GetChange and GetChangeDetail.
+	Meta string `url:"meta,omitempty"`
 }
 
 // QueryChanges lists changes visible to the caller.
@@ -892,6 +939,102 @@ func (s *ChangesService) SetReadyForReview(ctx context.Context, changeID string,
 	return s.client.Do(req, nil)
 }
 
+// SetWorkInProgress marks the change as not ready for review yet.
+// Changes may only be marked not ready by the owner, project owners or site administrators.
+// The request body does not need to include a WorkInProgressInput entity if no review comment is added.
+// Actions that create a new patch set in a WIP change default to notifying OWNER instead of ALL.
+// Marking a change work in progress also removes all users from the attention set.
+//
+// Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#set-work-in-pogress
+func (s *ChangesService) SetWorkInProgress(ctx context.Context, changeID string, input *WorkInProgressInput) (*Response, error) {
+	u := fmt.Sprintf("changes/%s/wip", changeID)
+
+	req, err := s.client.NewRequest(ctx, "POST", u, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(req, nil)
+}
+
+// MarkPrivate marks the change to be private.
+// Only open changes can be marked private.
+// Changes may only be marked private by the owner or site administrators.
+// The request body does not need to include a PrivateInput entity if no review comment is added.
+//
+// As response “201 Created” is returned if the change was marked private
+// and “200 OK” if the change was already private.
+//
+// Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#mark-private
+func (s *ChangesService) MarkPrivate(ctx context.Context, changeID string, input *PrivateInput) (*Response, error) {
+	u := fmt.Sprintf("changes/%s/private", changeID)
+
+	req, err := s.client.NewRequest(ctx, "POST", u, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(req, nil)
+}
+
+// UnmarkPrivate marks the change to be non-private.
+// Note users can only unmark own private changes.
+// The request body does not need to include a PrivateInput entity if no review comment is added.
+//
+// Please note that some proxies prohibit request bodies for DELETE requests.
+//
+// Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#unmark-private
+func (s *ChangesService) UnmarkPrivate(ctx context.Context, changeID string, input *PrivateInput) (*Response, error) {
+	u := fmt.Sprintf("changes/%s/private", changeID)
+	return s.client.DeleteRequest(ctx, u, input)
+}
+
+// IgnoreChange marks a change as ignored.
+// The change will not be shown in the incoming reviews dashboard, and email notifications will be suppressed.
+// Ignoring a change does not cause the change’s "updated" timestamp to be modified,
+// and the owner is not notified.
+//
+// Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#ignore
+func (s *ChangesService) IgnoreChange(ctx context.Context, changeID string) (*Response, error) {
+	return s.putChangeFlag(ctx, changeID, "ignore")
+}
+
+// UnignoreChange un-marks a change as ignored.
+//
+// Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#unignore
+func (s *ChangesService) UnignoreChange(ctx context.Context, changeID string) (*Response, error) {
+	return s.putChangeFlag(ctx, changeID, "unignore")
+}
+
+// MarkAsReviewed marks a change as reviewed.
+// This allows users to "de-highlight" changes in their dashboard until a new patch set is uploaded.
+//
+// Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#mark-as-reviewed
+func (s *ChangesService) MarkAsReviewed(ctx context.Context, changeID string) (*Response, error) {
+	return s.putChangeFlag(ctx, changeID, "reviewed")
+}
+
+// MarkAsUnreviewed marks a change as unreviewed.
+// This allows users to "highlight" changes in their dashboard.
+//
+// Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#mark-as-unreviewed
+func (s *ChangesService) MarkAsUnreviewed(ctx context.Context, changeID string) (*Response, error) {
+	return s.putChangeFlag(ctx, changeID, "unreviewed")
+}
+
+// putChangeFlag is an internal function to consolidate code used by IgnoreChange,
+// MarkAsReviewed and other similar functions.
+func (s *ChangesService) putChangeFlag(ctx context.Context, changeID, tail string) (*Response, error) {
+	u := fmt.Sprintf("changes/%s/%s", changeID, tail)
+
+	req, err := s.client.NewRequest(ctx, "PUT", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(req, nil)
+}
+
 // SetTopic sets the topic of a change.
 // The new topic must be provided in the request body inside a TopicInput entity.
 //
@@ -1041,6 +1184,29 @@ func (s *ChangesService) RebaseChange(ctx context.Context, changeID string, inpu
 	return s.change(ctx, "rebase", changeID, input)
 }
 
+// RebaseChain rebases an ancestry chain of changes.
+//
+// The operated change is treated as the chain tip. All unsubmitted ancestors are rebased,
+// either onto the destination branch or onto the base given in the RebaseInput entity.
+// With RebaseInput.AllowConflicts set, changes with conflicts are rebased as well and
+// RebaseChainInfo.ContainsGitConflicts is set.
+//
+// Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#rebase-chain
+func (s *ChangesService) RebaseChain(ctx context.Context, changeID string, input *RebaseInput) (*RebaseChainInfo, *Response, error) {
+	u := fmt.Sprintf("changes/%s/rebase:chain", changeID)
+	req, err := s.client.NewRequest(ctx, "POST", u, input)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	v := new(RebaseChainInfo)
+	resp, err := s.client.Do(req, v)
+	if err != nil {
+		return nil, resp, err
+	}
+	return v, resp, err
+}
+
 // RestoreChange restores a change.
 //
 // The request body does not need to include a RestoreInput entity if no review
@@ -1061,6 +1227,28 @@ func (s *ChangesService) RevertChange(ctx context.Context, changeID string, inpu
 	return s.change(ctx, "revert", changeID, input)
 }
 
+// RevertSubmission creates open revert changes for all of the changes of a certain submission.
+//
+// The changes of the submission may span multiple projects and branches, e.g. when they were
+// submitted together by topic. The revert changes are put into a common topic,
+// which can be set through the RevertInput entity.
+//
+// Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#revert-submission
+func (s *ChangesService) RevertSubmission(ctx context.Context, changeID string, input *RevertInput) (*RevertSubmissionInfo, *Response, error) {
+	u := fmt.Sprintf("changes/%s/revert_submission", changeID)
+	req, err := s.client.NewRequest(ctx, "POST", u, input)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	v := new(RevertSubmissionInfo)
+	resp, err := s.client.Do(req, v)
+	if err != nil {
+		return nil, resp, err
+	}
+	return v, resp, err
+}
+
 // MoveChange moves a change.
 //
 // The destination branch must be provided in the request body inside a MoveInput entity.
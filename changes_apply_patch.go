@@ -0,0 +1,222 @@
+package gerrit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ApplyPatchPatchSetInput entity contains information for creating a new patch set from a given patch.
+type ApplyPatchPatchSetInput struct {
+	Patch                 *ApplyPatchInput `json:"patch"`
+	CommitMessage         string           `json:"commit_message,omitempty"`
+	Base                  string           `json:"base,omitempty"`
+	Amend                 bool             `json:"amend,omitempty"`
+	ResponseFormatOptions []string         `json:"response_format_options,omitempty"`
+	Author                *AccountInput    `json:"author,omitempty"`
+}
+
+// ApplyPatch creates a new patch set on a destination change from the provided patch.
+//
+// The patch must be provided in the request body inside an ApplyPatchPatchSetInput entity.
+// If a base commit is given, the patch is applied on top of it. Otherwise it is applied on top of
+// the target change's branch tip, or on top of the current patch set if Amend is set.
+//
+// As response a ChangeInfo entity is returned that describes the destination change after applying the patch.
+//
+// Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#apply-patch
+func (s *ChangesService) ApplyPatch(ctx context.Context, changeID string, input *ApplyPatchPatchSetInput) (*ChangeInfo, *Response, error) {
+	u := fmt.Sprintf("changes/%s/patch:apply", changeID)
+
+	req, err := s.client.NewRequest(ctx, "POST", u, input)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	v := new(ChangeInfo)
+	resp, err := s.client.Do(req, v)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return v, resp, err
+}
+
+// ChangeProposal describes a modification to propose for review with ProposeChange.
+type ChangeProposal struct {
+	// ChangeID is the change to upload a new patch set to.
+	// If it is empty, a new change is created in Project and Branch.
+	ChangeID string
+	Project  string
+	Branch   string
+
+	// BaseCommit is the commit the modification is based on.
+	// It defaults to the tip of the branch.
+	BaseCommit string
+	// BaseChange is the change the modification is based on, i.e. the current patch set of that change.
+	// Only one of BaseCommit and BaseChange may be set.
+	BaseChange string
+
+	// Message is the commit message including the subject.
+	// It is required for new changes. For new patch sets, it defaults to the commit message
+	// of the current patch set, and the Change-Id of the change is added if it is missing.
+	Message string
+	// Footers are set in the commit message, replacing existing footers with the same key.
+	Footers []Footer
+
+	// Diff is a unified diff of the modification, as produced by "git diff".
+	// Either Diff or Files and DeleteFiles must be set.
+	Diff string
+	// Files maps file paths to their new content.
+	Files map[string]string
+	// DeleteFiles are the paths of files to delete.
+	DeleteFiles []string
+
+	// Topic and WorkInProgress are only used for new changes.
+	Topic          string
+	WorkInProgress bool
+}
+
+// ProposeChange creates a change or a new patch set from a unified diff or a set of file contents,
+// without the need for git push access.
+//
+// A diff is uploaded with CreateChange or ApplyPatch.
+// File contents are uploaded through a ChangeEditSession; for new changes this results in
+// a first patch set without modifications followed by the patch set with the files.
+// If uploading the files fails, the new change is deleted again.
+//
+// The returned ChangeInfo describes the change after the upload.
+func (s *ChangesService) ProposeChange(ctx context.Context, p *ChangeProposal) (*ChangeInfo, error) {
+	hasFiles := len(p.Files) > 0 || len(p.DeleteFiles) > 0
+	if (p.Diff == "") == !hasFiles {
+		return nil, errors.New("either a diff or files must be proposed")
+	}
+	if p.ChangeID == "" && (p.Project == "" || p.Branch == "") {
+		return nil, errors.New("project and branch are required to create a change")
+	}
+	if p.BaseCommit != "" && p.BaseChange != "" {
+		return nil, errors.New("only one of base commit and base change can be used")
+	}
+
+	parseMessage := func(text string) *CommitMessage {
+		message := ParseCommitMessage(text)
+		for _, f := range p.Footers {
+			message.SetFooter(f.Key, f.Value)
+		}
+		return message
+	}
+
+	if p.ChangeID == "" {
+		// Gerrit generates the Change-Id of new changes if it is missing.
+		message := parseMessage(p.Message)
+		if err := message.Validate(); err != nil && err != ErrMissingChangeID {
+			return nil, err
+		}
+		return s.proposeNewChange(ctx, p, message)
+	}
+
+	var opt *ChangeOptions
+	if p.Message == "" {
+		opt = &ChangeOptions{AdditionalFields: []string{"CURRENT_REVISION", "CURRENT_COMMIT"}}
+	}
+	change, _, err := s.GetChange(ctx, p.ChangeID, opt)
+	if err != nil {
+		return nil, err
+	}
+	text := p.Message
+	if text == "" {
+		rev, ok := change.Revisions[change.CurrentRevision]
+		if !ok {
+			return nil, fmt.Errorf("commit message of change %s is not available", p.ChangeID)
+		}
+		text = rev.Commit.Message
+	}
+	message := parseMessage(text)
+	if message.ChangeID() == "" {
+		if err := message.SetChangeID(change.ChangeID); err != nil {
+			return nil, err
+		}
+	}
+	if err := message.Validate(); err != nil {
+		return nil, err
+	}
+
+	if p.Diff != "" {
+		base := p.BaseCommit
+		if p.BaseChange != "" {
+			baseChange, _, err := s.GetChange(ctx, p.BaseChange, &ChangeOptions{AdditionalFields: []string{"CURRENT_REVISION"}})
+			if err != nil {
+				return nil, fmt.Errorf("retrieving the base change: %v", err)
+			}
+			base = baseChange.CurrentRevision
+		}
+		v, _, err := s.ApplyPatch(ctx, change.ID, &ApplyPatchPatchSetInput{
+			Patch:         &ApplyPatchInput{Patch: p.Diff},
+			CommitMessage: message.String(),
+			Base:          base,
+		})
+		return v, err
+	}
+
+	if p.BaseCommit != "" || p.BaseChange != "" {
+		return nil, errors.New("a base commit or change can't be used when proposing files for an existing change")
+	}
+	return s.proposeFiles(ctx, change.ID, p, message.String())
+}
+
+// proposeNewChange creates the change of a ChangeProposal without ChangeID.
+func (s *ChangesService) proposeNewChange(ctx context.Context, p *ChangeProposal, message *CommitMessage) (*ChangeInfo, error) {
+	input := &ChangeInput{
+		Project:        p.Project,
+		Branch:         p.Branch,
+		Subject:        message.String(),
+		Topic:          p.Topic,
+		WorkInProgress: p.WorkInProgress,
+		BaseChange:     p.BaseChange,
+		BaseCommit:     p.BaseCommit,
+	}
+	if p.Diff != "" {
+		input.Patch = &ApplyPatchInput{Patch: p.Diff}
+	}
+	change, _, err := s.CreateChange(ctx, input)
+	if err != nil || p.Diff != "" {
+		return change, err
+	}
+
+	v, err := s.proposeFiles(ctx, change.ID, p, "")
+	if err != nil {
+		// Don't leave the empty change behind.
+		if _, deleteErr := s.DeleteChange(ctx, change.ID); deleteErr != nil {
+			return nil, fmt.Errorf("proposing files for new change %s: %v (deleting the change failed as well: %v)", change.ID, err, deleteErr)
+		}
+		return nil, fmt.Errorf("proposing files for new change %s: %v", change.ID, err)
+	}
+	return v, nil
+}
+
+// proposeFiles uploads the files of a ChangeProposal as new patch set of the change.
+// The commit message is only changed if message is not empty.
+func (s *ChangesService) proposeFiles(ctx context.Context, changeID string, p *ChangeProposal, message string) (*ChangeInfo, error) {
+	session := s.NewChangeEditSession(changeID)
+	paths := make([]string, 0, len(p.Files))
+	for path := range p.Files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		session.WriteFile(path, p.Files[path])
+	}
+	for _, path := range p.DeleteFiles {
+		session.DeleteFile(path)
+	}
+	if message != "" {
+		session.SetMessage(message)
+	}
+	if err := session.Commit(ctx, "ALL"); err != nil {
+		return nil, err
+	}
+
+	change, _, err := s.GetChange(ctx, changeID, nil)
+	return change, err
+}
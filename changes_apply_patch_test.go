@@ -0,0 +1,250 @@
+package gerrit_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/andygrunwald/go-gerrit"
+)
+
+const testUnifiedDiff = `diff --git a/README.md b/README.md
+--- a/README.md
++++ b/README.md
+@@ -1 +1 @@
+-# Old
++# New
+`
+
+func TestChangesService_ApplyPatch(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/changes/123/patch:apply", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		var input gerrit.ApplyPatchPatchSetInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			t.Error(err)
+		}
+		if input.Patch == nil || input.Patch.Patch != testUnifiedDiff || input.Base != "abc" {
+			t.Errorf("Unexpected input %+v", input)
+		}
+		fmt.Fprint(w, `{"_number": 123, "current_revision": "def"}`)
+	})
+
+	change, _, err := testClient.Changes.ApplyPatch(context.Background(), "123", &gerrit.ApplyPatchPatchSetInput{
+		Patch: &gerrit.ApplyPatchInput{Patch: testUnifiedDiff},
+		Base:  "abc",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if change.CurrentRevision != "def" {
+		t.Errorf("Unexpected change %+v", change)
+	}
+}
+
+func TestChangesService_ProposeChange_NewChange(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/changes/", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		var input gerrit.ChangeInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			t.Error(err)
+		}
+		want := gerrit.ChangeInput{
+			Project:    "tools",
+			Branch:     "main",
+			Subject:    "Update README\n\nBug: 42\n",
+			Topic:      "docs",
+			BaseCommit: "abc",
+			Patch:      &gerrit.ApplyPatchInput{Patch: testUnifiedDiff},
+		}
+		if !reflect.DeepEqual(input, want) {
+			t.Errorf("input:\ngot:  %+v\nwant: %+v", input, want)
+		}
+		fmt.Fprint(w, `{"id": "tools~main~I1", "_number": 7}`)
+	})
+
+	change, err := testClient.Changes.ProposeChange(context.Background(), &gerrit.ChangeProposal{
+		Project:    "tools",
+		Branch:     "main",
+		BaseCommit: "abc",
+		Message:    "Update README",
+		Footers:    []gerrit.Footer{{Key: gerrit.FooterBug, Value: "42"}},
+		Diff:       testUnifiedDiff,
+		Topic:      "docs",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if change.Number != 7 {
+		t.Errorf("Unexpected change %+v", change)
+	}
+}
+
+func TestChangesService_ProposeChange_Files(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/changes/tools~main~I8473b95934b5732ac55d26311a706c9c2bde9940", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": "tools~main~I8473b95934b5732ac55d26311a706c9c2bde9940", "change_id": "I8473b95934b5732ac55d26311a706c9c2bde9940", "_number": 7, "current_revision": "abc"}`)
+	})
+	var calls []string
+	prefix := "/changes/tools~main~I8473b95934b5732ac55d26311a706c9c2bde9940/"
+	testMux.HandleFunc(prefix, func(w http.ResponseWriter, r *http.Request) {
+		call := r.Method + " " + r.URL.Path[len(prefix):]
+		if call == "GET edit" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if call == "PUT edit:message" {
+			var input gerrit.ChangeEditMessageInput
+			if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+				t.Error(err)
+			}
+			if want := "Regenerate\n\nChange-Id: I8473b95934b5732ac55d26311a706c9c2bde9940\n"; input.Message != want {
+				t.Errorf("Message:\ngot:  %q\nwant: %q", input.Message, want)
+			}
+		}
+		calls = append(calls, call)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	_, err := testClient.Changes.ProposeChange(context.Background(), &gerrit.ChangeProposal{
+		ChangeID:    "tools~main~I8473b95934b5732ac55d26311a706c9c2bde9940",
+		Message:     "Regenerate",
+		Files:       map[string]string{"b.txt": "b", "a.txt": "a"},
+		DeleteFiles: []string{"c.txt"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"PUT edit/a.txt", "PUT edit/b.txt", "DELETE edit/c.txt", "PUT edit:message", "POST edit:publish"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls:\ngot:  %v\nwant: %v", calls, want)
+	}
+}
+
+func TestChangesService_ProposeChange_BaseChange(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/changes/tools~7", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": "tools~main~I8473b95934b5732ac55d26311a706c9c2bde9940", "change_id": "I8473b95934b5732ac55d26311a706c9c2bde9940", "_number": 7}`)
+	})
+	testMux.HandleFunc("/changes/tools~6", func(w http.ResponseWriter, r *http.Request) {
+		testFormValues(t, r, testValues{"o": "CURRENT_REVISION"})
+		fmt.Fprint(w, `{"id": "tools~main~I1", "_number": 6, "current_revision": "base6"}`)
+	})
+	testMux.HandleFunc("/changes/tools~main~I8473b95934b5732ac55d26311a706c9c2bde9940/patch:apply", func(w http.ResponseWriter, r *http.Request) {
+		var input gerrit.ApplyPatchPatchSetInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			t.Error(err)
+		}
+		if input.Base != "base6" {
+			t.Errorf("Base = %q, want %q", input.Base, "base6")
+		}
+		fmt.Fprint(w, `{"_number": 7}`)
+	})
+
+	if _, err := testClient.Changes.ProposeChange(context.Background(), &gerrit.ChangeProposal{
+		ChangeID:   "tools~7",
+		BaseChange: "tools~6",
+		Message:    "Update README",
+		Diff:       testUnifiedDiff,
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestChangesService_ProposeChange_NewChangeRollback(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/changes/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": "tools~main~I1", "_number": 7}`)
+	})
+	var calls []string
+	testMux.HandleFunc("/changes/tools~main~I1", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" change")
+		w.WriteHeader(http.StatusNoContent)
+	})
+	testMux.HandleFunc("/changes/tools~main~I1/", func(w http.ResponseWriter, r *http.Request) {
+		call := r.Method + " " + r.URL.Path[len("/changes/tools~main~I1/"):]
+		calls = append(calls, call)
+		if call == "PUT edit/a.txt" {
+			http.Error(w, "conflict", http.StatusConflict)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	_, err := testClient.Changes.ProposeChange(context.Background(), &gerrit.ChangeProposal{
+		Project: "tools",
+		Branch:  "main",
+		Message: "Add a",
+		Files:   map[string]string{"a.txt": "a"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "tools~main~I1") {
+		t.Errorf("ProposeChange() error = %v, want error mentioning the change", err)
+	}
+	want := []string{"GET edit", "PUT edit/a.txt", "DELETE edit", "DELETE change"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls:\ngot:  %v\nwant: %v", calls, want)
+	}
+}
+
+func TestChangesService_ProposeChange_KeepMessage(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/changes/tools~7", func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.Query()["o"], []string{"CURRENT_REVISION", "CURRENT_COMMIT"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("o = %v, want %v", got, want)
+		}
+		fmt.Fprint(w, `{"id": "tools~main~I8473b95934b5732ac55d26311a706c9c2bde9940", "change_id": "I8473b95934b5732ac55d26311a706c9c2bde9940", "_number": 7,
+  "current_revision": "abc", "revisions": {"abc": {"commit": {"message": "Update README\n\nExplain the flags.\n\nChange-Id: I8473b95934b5732ac55d26311a706c9c2bde9940\n"}}}}`)
+	})
+	testMux.HandleFunc("/changes/tools~main~I8473b95934b5732ac55d26311a706c9c2bde9940/patch:apply", func(w http.ResponseWriter, r *http.Request) {
+		var input gerrit.ApplyPatchPatchSetInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			t.Error(err)
+		}
+		if want := "Update README\n\nExplain the flags.\n\nChange-Id: I8473b95934b5732ac55d26311a706c9c2bde9940\nBug: 42\n"; input.CommitMessage != want {
+			t.Errorf("CommitMessage:\ngot:  %q\nwant: %q", input.CommitMessage, want)
+		}
+		fmt.Fprint(w, `{"_number": 7}`)
+	})
+
+	if _, err := testClient.Changes.ProposeChange(context.Background(), &gerrit.ChangeProposal{
+		ChangeID: "tools~7",
+		Footers:  []gerrit.Footer{{Key: gerrit.FooterBug, Value: "42"}},
+		Diff:     testUnifiedDiff,
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestChangesService_ProposeChange_Invalid(t *testing.T) {
+	setup()
+	defer teardown()
+
+	for _, p := range []*gerrit.ChangeProposal{
+		{Project: "p", Branch: "b", Diff: testUnifiedDiff},
+		{Project: "p", Branch: "b", Message: "m"},
+		{Project: "p", Branch: "b", Message: "m", Diff: testUnifiedDiff, Files: map[string]string{"a": "a"}},
+		{Project: "p", Message: "m", Diff: testUnifiedDiff},
+		{Project: "p", Branch: "b", Message: "m", Diff: testUnifiedDiff, BaseCommit: "abc", BaseChange: "42"},
+	} {
+		if _, err := testClient.Changes.ProposeChange(context.Background(), p); err == nil {
+			t.Errorf("ProposeChange(%+v) succeeded, want error", p)
+		}
+	}
+}
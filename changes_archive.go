@@ -0,0 +1,419 @@
+package gerrit
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/bzip2"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Archive formats supported by GetRevisionArchive.
+// Which formats are enabled depends on the download.archive setting of the Gerrit server.
+const (
+	ArchiveFormatTGZ  = "tgz"
+	ArchiveFormatTar  = "tar"
+	ArchiveFormatTBZ2 = "tbz2"
+	ArchiveFormatTXZ  = "txz"
+	ArchiveFormatZip  = "zip"
+)
+
+// GetRevisionArchive downloads the tree of a revision as archive in the given format,
+// e.g. ArchiveFormatTGZ.
+//
+// The archive is not buffered: the returned io.ReadCloser is the body of the response
+// and must be closed by the caller.
+//
+// Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#get-archive
+func (s *ChangesService) GetRevisionArchive(ctx context.Context, changeID, revisionID, format string) (io.ReadCloser, *Response, error) {
+	u := fmt.Sprintf("changes/%s/revisions/%s/archive", changeID, revisionID)
+	return s.getArchive(ctx, u, format)
+}
+
+// getArchive requests an archive in the given format from u and returns the unread body of the response.
+func (s *ChangesService) getArchive(ctx context.Context, u, format string) (io.ReadCloser, *Response, error) {
+	u, err := addOptions(u, struct {
+		Format string `url:"format"`
+	}{format})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := s.client.Do(req, nil)
+	if err != nil {
+		if resp != nil {
+			resp.Body.Close() // nolint: errcheck
+		}
+		return nil, resp, err
+	}
+
+	return resp.Body, resp, nil
+}
+
+// ExtractArchive extracts an archive as returned by GetRevisionArchive into dir,
+// which is created if needed.
+//
+// Entries that would be written outside of dir, either through their path or through
+// a symbolic link, are rejected with an error.
+// Files that already exist in dir are overwritten.
+// ArchiveFormatTXZ is not supported, as the standard library lacks xz decompression.
+func ExtractArchive(r io.Reader, format, dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	if format == ArchiveFormatZip {
+		return extractZip(r, dir)
+	}
+	tr, err := decompressTar(r, format)
+	if err != nil {
+		return err
+	}
+	defer tr.Close() // nolint: errcheck
+	return extractTar(tr, dir)
+}
+
+// decompressTar returns the tar stream of an archive in one of the tar based formats.
+func decompressTar(r io.Reader, format string) (io.ReadCloser, error) {
+	switch format {
+	case ArchiveFormatTar:
+		return io.NopCloser(r), nil
+	case ArchiveFormatTGZ:
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return gz, nil
+	case ArchiveFormatTBZ2:
+		return io.NopCloser(bzip2.NewReader(r)), nil
+	default:
+		return nil, fmt.Errorf("unsupported archive format %q", format)
+	}
+}
+
+// walkArchiveFiles calls fn with the name and content of every regular file of an archive.
+// Directories are skipped, other entries are rejected.
+func walkArchiveFiles(r io.Reader, format string, fn func(name string, r io.Reader) error) error {
+	if format == ArchiveFormatZip {
+		zr, cleanup, err := readZip(r)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		for _, f := range zr.File {
+			if f.Mode().IsDir() {
+				continue
+			}
+			if !f.Mode().IsRegular() {
+				return fmt.Errorf("unsupported entry %q in archive", f.Name)
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return err
+			}
+			err = fn(f.Name, rc)
+			rc.Close() // nolint: errcheck
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	rc, err := decompressTar(r, format)
+	if err != nil {
+		return err
+	}
+	defer rc.Close() // nolint: errcheck
+	tr := tar.NewReader(rc)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch hdr.Typeflag {
+		case tar.TypeXGlobalHeader, tar.TypeDir:
+			continue
+		case tar.TypeReg:
+			if err := fn(hdr.Name, tr); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unsupported entry %q of type %q in archive", hdr.Name, hdr.Typeflag)
+		}
+	}
+}
+
+// archivePath returns the path of the archive entry name within dir.
+// It fails if the entry would end up outside of dir.
+func archivePath(dir, name string) (string, error) {
+	clean := path.Clean("/" + name)
+	if clean == "/" || name == "" || strings.HasPrefix(name, "/") || containsDotDot(name) {
+		return "", fmt.Errorf("invalid path %q in archive", name)
+	}
+	return filepath.Join(dir, filepath.FromSlash(clean[1:])), nil
+}
+
+// containsDotDot reports whether any element of the slash-separated path name is "..".
+func containsDotDot(name string) bool {
+	for _, e := range strings.Split(strings.Replace(name, `\`, "/", -1), "/") {
+		if e == ".." {
+			return true
+		}
+	}
+	return false
+}
+
+// writeArchiveFile creates the file target within dir with the content of r.
+func writeArchiveFile(dir, target string, r io.Reader, mode os.FileMode) error {
+	if err := mkdirInDir(dir, filepath.Dir(target)); err != nil {
+		return err
+	}
+	// Remove existing files, so that an existing symbolic link isn't followed.
+	if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode.Perm()|0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close() // nolint: errcheck
+		return err
+	}
+	return f.Close()
+}
+
+// mkdirInDir creates the directory path and its parents.
+// It fails if path resolves to a location outside of dir through the symbolic links on disk.
+func mkdirInDir(dir, path string) error {
+	root, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return err
+	}
+
+	// Resolve the deepest existing prefix of path, the remaining elements are created as directories.
+	existing, rest := path, ""
+	for {
+		_, err := os.Lstat(existing)
+		if err == nil {
+			break
+		}
+		if !os.IsNotExist(err) {
+			return err
+		}
+		rest = filepath.Join(filepath.Base(existing), rest)
+		existing = filepath.Dir(existing)
+	}
+	resolved, err := filepath.EvalSymlinks(existing)
+	if err != nil {
+		return err
+	}
+	if !isWithin(root, filepath.Join(resolved, rest)) {
+		return fmt.Errorf("path %q resolves outside of the target directory", path)
+	}
+	return os.MkdirAll(path, 0o755)
+}
+
+// isWithin reports whether the path p is root or below root.
+func isWithin(root, p string) bool {
+	rel, err := filepath.Rel(root, p)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func extractTar(r io.Reader, dir string) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if hdr.Typeflag == tar.TypeXGlobalHeader {
+			// git archive stores the commit id in a global header.
+			continue
+		}
+
+		target, err := archivePath(dir, hdr.Name)
+		if err != nil {
+			return err
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			err = mkdirInDir(dir, target)
+		case tar.TypeReg:
+			err = writeArchiveFile(dir, target, tr, os.FileMode(hdr.Mode))
+		case tar.TypeSymlink:
+			err = createSymlink(dir, target, hdr.Linkname)
+		default:
+			err = fmt.Errorf("unsupported entry %q of type %q in archive", hdr.Name, hdr.Typeflag)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// createSymlink creates the symbolic link target within dir pointing to linkname.
+// It fails if the link would point outside of dir.
+func createSymlink(dir, target, linkname string) error {
+	if filepath.IsAbs(linkname) || strings.HasPrefix(linkname, "/") {
+		return fmt.Errorf("symbolic link %q points to absolute path %q", target, linkname)
+	}
+	if err := mkdirInDir(dir, filepath.Dir(target)); err != nil {
+		return err
+	}
+
+	root, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return err
+	}
+	parent, err := filepath.EvalSymlinks(filepath.Dir(target))
+	if err != nil {
+		return err
+	}
+	resolved, err := resolveLinkTarget(parent, linkname)
+	if err != nil || !isWithin(root, resolved) {
+		return fmt.Errorf("symbolic link %q points outside of the target directory", target)
+	}
+
+	if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return os.Symlink(linkname, target)
+}
+
+// resolveLinkTarget returns the path the relative link target linkname resolves to
+// from the resolved directory parent.
+//
+// The elements of linkname are resolved one by one against the symbolic links that exist on disk,
+// so that links like "s/s/.." can't escape through a link "s -> .".
+// Elements that don't exist yet are taken literally. As they could be replaced by
+// symbolic links later, a ".." following them is rejected.
+func resolveLinkTarget(parent, linkname string) (string, error) {
+	cur, unresolved := parent, false
+	for _, e := range strings.Split(strings.Replace(linkname, `\`, "/", -1), "/") {
+		switch e {
+		case "", ".":
+			continue
+		case "..":
+			if unresolved {
+				return "", fmt.Errorf("symbolic link target %q can't be resolved", linkname)
+			}
+			cur = filepath.Dir(cur)
+			continue
+		}
+
+		next := filepath.Join(cur, e)
+		if !unresolved {
+			fi, err := os.Lstat(next)
+			switch {
+			case os.IsNotExist(err):
+				unresolved = true
+			case err != nil:
+				return "", err
+			case fi.Mode()&os.ModeSymlink != 0:
+				resolved, err := filepath.EvalSymlinks(next)
+				if os.IsNotExist(err) {
+					unresolved = true
+				} else if err != nil {
+					return "", err
+				} else {
+					next = resolved
+				}
+			}
+		}
+		cur = next
+	}
+	return cur, nil
+}
+
+// readZip opens a zip archive read from r.
+// Zip archives can only be read with random access, so the archive is buffered in a temporary file,
+// which is removed by calling cleanup.
+func readZip(r io.Reader) (zr *zip.Reader, cleanup func(), err error) {
+	tmp, err := os.CreateTemp("", "gerrit-archive-*.zip")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup = func() {
+		tmp.Close()           // nolint: errcheck
+		os.Remove(tmp.Name()) // nolint: errcheck
+	}
+
+	size, err := io.Copy(tmp, r)
+	if err == nil {
+		zr, err = zip.NewReader(tmp, size)
+	}
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	return zr, cleanup, nil
+}
+
+func extractZip(r io.Reader, dir string) error {
+	zr, cleanup, err := readZip(r)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	for _, f := range zr.File {
+		target, err := archivePath(dir, f.Name)
+		if err != nil {
+			return err
+		}
+		mode := f.Mode()
+		switch {
+		case mode.IsDir():
+			err = mkdirInDir(dir, target)
+		case mode&os.ModeSymlink != 0:
+			err = extractZipSymlink(dir, target, f)
+		default:
+			err = extractZipFile(dir, target, f)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func extractZipFile(dir, target string, f *zip.File) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close() // nolint: errcheck
+	return writeArchiveFile(dir, target, rc, f.Mode())
+}
+
+func extractZipSymlink(dir, target string, f *zip.File) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close() // nolint: errcheck
+	linkname, err := io.ReadAll(rc)
+	if err != nil {
+		return err
+	}
+	return createSymlink(dir, target, string(linkname))
+}
@@ -0,0 +1,133 @@
+package gerrit_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/andygrunwald/go-gerrit"
+)
+
+type archiveEntry struct {
+	name, content, link string
+}
+
+func buildTGZ(t *testing.T, entries []archiveEntry) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, e := range entries {
+		hdr := &tar.Header{Name: e.name, Mode: 0o644, Size: int64(len(e.content)), Typeflag: tar.TypeReg}
+		if e.link != "" {
+			hdr = &tar.Header{Name: e.name, Mode: 0o777, Linkname: e.link, Typeflag: tar.TypeSymlink}
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(e.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestChangesService_GetRevisionArchive(t *testing.T) {
+	setup()
+	defer teardown()
+
+	archive := buildTGZ(t, []archiveEntry{
+		{name: "README.md", content: "# Project\n"},
+		{name: "src/main.go", content: "package main\n"},
+		{name: "docs", link: "src"},
+	})
+	testMux.HandleFunc("/changes/123/revisions/2/archive", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testFormValues(t, r, testValues{"format": "tgz"})
+		w.Header().Set("Content-Type", "application/x-gzip")
+		w.Write(archive) // nolint: errcheck
+	})
+
+	rc, _, err := testClient.Changes.GetRevisionArchive(context.Background(), "123", "2", gerrit.ArchiveFormatTGZ)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+
+	dir := t.TempDir()
+	if err := gerrit.ExtractArchive(rc, gerrit.ArchiveFormatTGZ, dir); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{"README.md": "# Project\n", "src/main.go": "package main\n", "docs/main.go": "package main\n"} {
+		got, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Error(err)
+		} else if string(got) != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestExtractArchive_PathTraversal(t *testing.T) {
+	tests := map[string][]archiveEntry{
+		"parent directory":      {{name: "../evil", content: "x"}},
+		"nested parent":         {{name: "a/../../evil", content: "x"}},
+		"absolute path":         {{name: "/tmp/evil", content: "x"}},
+		"symlink outside":       {{name: "link", link: "../.."}},
+		"absolute symlink":      {{name: "link", link: "/etc"}},
+		"symlink in subfolder":  {{name: "a/link", link: "../../x"}},
+		"symlink below symlink": {{name: "s", link: "."}, {name: "s/link", link: "../x"}},
+		"chained symlinks":      {{name: "s", link: "."}, {name: "t", link: "s/s/.."}, {name: "t/evil", content: "x"}},
+		"chained symlinks up":   {{name: "s", link: "."}, {name: "t", link: "s/s/../.."}, {name: "t/evil", content: "x"}},
+		"dot dot after missing": {{name: "t", link: "m/.."}, {name: "m", link: ".."}, {name: "t/evil", content: "x"}},
+	}
+	for name, entries := range tests {
+		t.Run(name, func(t *testing.T) {
+			root := t.TempDir()
+			dir := filepath.Join(root, "out")
+			if err := gerrit.ExtractArchive(bytes.NewReader(buildTGZ(t, entries)), gerrit.ArchiveFormatTGZ, dir); err == nil {
+				t.Error("Expected error")
+			}
+			for _, outside := range []string{root, filepath.Dir(root)} {
+				if _, err := os.Lstat(filepath.Join(outside, "evil")); err == nil {
+					t.Errorf("File was written outside of the target directory to %s", outside)
+				}
+			}
+		})
+	}
+}
+
+func TestExtractArchive_Zip(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range map[string]string{"a.txt": "a", "b/c.txt": "c"} {
+		f, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.WriteString(f, content) // nolint: errcheck
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	if err := gerrit.ExtractArchive(&buf, gerrit.ArchiveFormatZip, dir); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := os.ReadFile(filepath.Join(dir, "b", "c.txt")); err != nil || string(got) != "c" {
+		t.Errorf("b/c.txt = %q, %v", got, err)
+	}
+}
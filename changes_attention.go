@@ -3,6 +3,7 @@ package gerrit
 import (
 	"context"
 	"fmt"
+	"strconv"
 )
 
 // AttentionSetInfo entity contains details of users that are in the attention set.
@@ -20,6 +21,13 @@ type AttentionSetInfo struct {
 // Doc: https://gerrit-review.googlesource.com/Documentation/user-notify.html#recipient-types
 type RecipientType string
 
+// Recipient types that can be used as keys of NotifyDetails.
+const (
+	RecipientTo  RecipientType = "TO"
+	RecipientCC  RecipientType = "CC"
+	RecipientBCC RecipientType = "BCC"
+)
+
 // AttentionSetInput entity contains details for adding users to the attention
 // set and removing them from it.
 //
@@ -31,6 +39,49 @@ type AttentionSetInput struct {
 	NotifyDetails map[RecipientType]NotifyInfo `json:"notify_details,omitempty"`
 }
 
+// GetAttentionSet returns all users that are currently in the attention set.
+//
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#get-attention-set
+func (s *ChangesService) GetAttentionSet(ctx context.Context, changeID string) ([]AttentionSetInfo, *Response, error) {
+	u := fmt.Sprintf("changes/%s/attention", changeID)
+
+	req, err := s.client.NewRequest(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var v []AttentionSetInfo
+	resp, err := s.client.Do(req, &v)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return v, resp, err
+}
+
+// AddToAttentionSet adds a single user to the attention set of a change.
+// AttentionSetInput.User and AttentionSetInput.Reason must be provided.
+//
+// As response an AccountInfo entity is returned that describes the added user.
+//
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#add-to-attention-set
+func (s *ChangesService) AddToAttentionSet(ctx context.Context, changeID string, input *AttentionSetInput) (*AccountInfo, *Response, error) {
+	u := fmt.Sprintf("changes/%s/attention", changeID)
+
+	req, err := s.client.NewRequest(ctx, "POST", u, input)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	v := new(AccountInfo)
+	resp, err := s.client.Do(req, v)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return v, resp, err
+}
+
 // RemoveAttention deletes a single user from the attention set of a change.
 // AttentionSetInput.Input must be provided
 //
@@ -40,3 +91,29 @@ func (s *ChangesService) RemoveAttention(ctx context.Context, changeID, accountI
 
 	return s.client.DeleteRequest(ctx, u, input)
 }
+
+// AttentionFor returns the attention set entry of the given account
+// and reports whether the account is in the attention set of the change.
+//
+// The ChangeInfo must have been retrieved with the attention set,
+// which Gerrit includes by default.
+func (c *ChangeInfo) AttentionFor(accountID int) (AttentionSetInfo, bool) {
+	info, ok := c.AttentionSet[strconv.Itoa(accountID)]
+	return info, ok
+}
+
+// ChangesNeedingAttention returns the changes of a query result
+// that have the given account in their attention set.
+// The order of changes is preserved.
+//
+// To find the changes of the calling user, query for "attention:self" or pass
+// the result of any other query, e.g. "owner:self status:open".
+func ChangesNeedingAttention(changes []ChangeInfo, accountID int) []ChangeInfo {
+	var result []ChangeInfo
+	for _, change := range changes {
+		if _, ok := change.AttentionFor(accountID); ok {
+			result = append(result, change)
+		}
+	}
+	return result
+}
@@ -0,0 +1,97 @@
+package gerrit_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andygrunwald/go-gerrit"
+)
+
+func TestChangesService_GetAttentionSet(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, "/changes/123/attention")
+
+		_, err := fmt.Fprint(w, `)]}'
+[
+  {
+    "account": {"_account_id": 1000096, "name": "John Doe"},
+    "last_update": "2013-02-21 11:16:36.775000000",
+    "reason": "reviewer or cc replied"
+  }
+]`)
+		if err != nil {
+			t.Error(err)
+		}
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	client := newClient(ctx, t, ts)
+	got, _, err := client.Changes.GetAttentionSet(ctx, "123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Account.AccountID != 1000096 || got[0].Reason != "reviewer or cc replied" {
+		t.Errorf("Unexpected attention set %+v", got)
+	}
+}
+
+func TestChangesService_AddToAttentionSet(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testRequestURL(t, r, "/changes/123/attention")
+
+		var input gerrit.AttentionSetInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			t.Error(err)
+		}
+		if input.User != "jdoe" || input.Reason != "reminder" || input.Notify != "NONE" {
+			t.Errorf("Unexpected input %+v", input)
+		}
+
+		_, err := fmt.Fprint(w, `)]}'
+{"_account_id": 1000096, "name": "John Doe"}`)
+		if err != nil {
+			t.Error(err)
+		}
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	client := newClient(ctx, t, ts)
+	got, _, err := client.Changes.AddToAttentionSet(ctx, "123", &gerrit.AttentionSetInput{
+		User:   "jdoe",
+		Reason: "reminder",
+		Notify: "NONE",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.AccountID != 1000096 {
+		t.Errorf("AccountID = %d, want 1000096", got.AccountID)
+	}
+}
+
+func TestChangesNeedingAttention(t *testing.T) {
+	changes := []gerrit.ChangeInfo{
+		{Number: 1, AttentionSet: map[string]gerrit.AttentionSetInfo{"1000096": {Reason: "added"}}},
+		{Number: 2},
+		{Number: 3, AttentionSet: map[string]gerrit.AttentionSetInfo{"1000097": {Reason: "added"}}},
+		{Number: 4, AttentionSet: map[string]gerrit.AttentionSetInfo{"1000096": {Reason: "replied"}, "1000097": {}}},
+	}
+
+	got := gerrit.ChangesNeedingAttention(changes, 1000096)
+	if len(got) != 2 || got[0].Number != 1 || got[1].Number != 4 {
+		t.Errorf("Unexpected changes %+v", got)
+	}
+
+	info, ok := changes[3].AttentionFor(1000096)
+	if !ok || info.Reason != "replied" {
+		t.Errorf("AttentionFor() = %+v, %t", info, ok)
+	}
+}
@@ -0,0 +1,352 @@
+package gerrit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ChangeInfoDifference entity contains the difference between two meta revisions of a change.
+// Added and Removed only contain the fields that differ.
+type ChangeInfoDifference struct {
+	Added   ChangeInfo `json:"added"`
+	Removed ChangeInfo `json:"removed"`
+}
+
+// MetaDiffOptions specifies the parameters for GetMetaDiff call.
+type MetaDiffOptions struct {
+	// Old is the SHA-1 of the older meta revision.
+	// If not set, the parent of Meta is used.
+	Old string `url:"old,omitempty"`
+
+	// Meta is the SHA-1 of the newer meta revision.
+	// If not set, the current meta revision is used.
+	Meta string `url:"meta,omitempty"`
+
+	// Additional fields of the ChangeInfo entities to compare, like in ChangeOptions.
+	AdditionalFields []string `url:"o,omitempty"`
+}
+
+// GetMetaDiff retrieves the difference between two meta revisions of a change.
+//
+// Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#get-meta-diff
+func (s *ChangesService) GetMetaDiff(ctx context.Context, changeID string, opt *MetaDiffOptions) (*ChangeInfoDifference, *Response, error) {
+	u := fmt.Sprintf("changes/%s/meta_diff", changeID)
+
+	u, err := addOptions(u, opt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	v := new(ChangeInfoDifference)
+	resp, err := s.client.Do(req, v)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return v, resp, err
+}
+
+// AuditEventKind classifies an AuditEvent.
+type AuditEventKind string
+
+const (
+	// AuditLabel is a vote that was cast or removed.
+	AuditLabel AuditEventKind = "LABEL"
+	// AuditReviewer is a reviewer or CC that was added or removed.
+	AuditReviewer AuditEventKind = "REVIEWER"
+	// AuditTopic is a change of the topic.
+	AuditTopic AuditEventKind = "TOPIC"
+	// AuditHashtags is an addition or removal of hashtags.
+	AuditHashtags AuditEventKind = "HASHTAGS"
+)
+
+// AuditEvent is a single entry of the audit log of a change.
+type AuditEvent struct {
+	Kind AuditEventKind
+	Date Timestamp
+	// Account is the account that performed the action.
+	Account AccountInfo
+	// RealAccount is the account that performed the action on behalf of Account, if any.
+	RealAccount *AccountInfo
+	// PatchSet is the patch set a vote was cast on, if known.
+	PatchSet int
+
+	// Label and Value describe a vote. A removed vote has value 0.
+	Label string
+	Value int
+	// Voter is the account whose vote changed.
+	// It differs from Account if the vote was removed by someone else.
+	Voter AccountInfo
+
+	// Reviewer and State describe a reviewer update.
+	// State is one of "REVIEWER", "CC" or "REMOVED".
+	Reviewer AccountInfo
+	State    string
+
+	// Old and New are the topic before and after an AuditTopic event.
+	Old, New string
+	// Added and Removed are the hashtags of an AuditHashtags event.
+	Added, Removed []string
+}
+
+var (
+	auditVotePattern          = regexp.MustCompile(`^(-?)([A-Za-z0-9-]+?)([+-]\d+)?$`)
+	auditRemovedVotePattern   = regexp.MustCompile(`^Removed ([A-Za-z0-9-]+?)[+-]\d+ by (.+)$`)
+	auditTopicSetPattern      = regexp.MustCompile(`^Topic set to (.+)$`)
+	auditTopicChangedPattern  = regexp.MustCompile(`^Topic changed from (.+) to (.+)$`)
+	auditTopicRemovedPattern  = regexp.MustCompile(`^Topic (.+) removed$`)
+	auditHashtagsPattern      = regexp.MustCompile(`^Hashtags? (added|removed): (.+)$`)
+	auditPatchSetVotesPattern = regexp.MustCompile(`^Patch Set \d+:(.*)$`)
+)
+
+// auditFields are the additional fields needed to build an audit log.
+var auditFields = []string{"MESSAGES", "DETAILED_ACCOUNTS", "DETAILED_LABELS", "REVIEWER_UPDATES"}
+
+// GetAuditLog returns the chronological audit log of the votes, reviewer updates,
+// topic and hashtag edits of a change.
+//
+// The log is built from the NoteDb history of the change: starting at the current meta revision,
+// GetMetaDiff is called for every meta revision and its parent, and the differences are converted
+// with ChangeInfoDifference.AuditEvents. This needs one request per update of the change.
+//
+// If the server doesn't provide meta revisions, the log is built from the change messages
+// with NewAuditLog instead.
+func (s *ChangesService) GetAuditLog(ctx context.Context, changeID string) ([]AuditEvent, *Response, error) {
+	change, resp, err := s.GetChange(ctx, changeID, &ChangeOptions{AdditionalFields: auditFields})
+	if err != nil {
+		return nil, resp, err
+	}
+	if change.MetaRevID == "" {
+		return NewAuditLog(change), resp, nil
+	}
+
+	// Diffs are retrieved from the newest to the oldest meta revision.
+	var diffs [][]AuditEvent
+	seen := map[string]bool{}
+	for meta := change.MetaRevID; meta != "" && !seen[meta]; {
+		seen[meta] = true
+		diff, resp, err := s.GetMetaDiff(ctx, changeID, &MetaDiffOptions{Meta: meta, AdditionalFields: auditFields})
+		if err != nil {
+			if len(diffs) == 0 && resp != nil && resp.StatusCode == http.StatusNotFound {
+				return NewAuditLog(change), resp, nil
+			}
+			return nil, resp, err
+		}
+		diffs = append(diffs, diff.AuditEvents())
+		meta = diff.Removed.MetaRevID
+	}
+
+	var events []AuditEvent
+	for i := len(diffs) - 1; i >= 0; i-- {
+		events = append(events, diffs[i]...)
+	}
+	sortAuditEvents(events)
+	return events, resp, nil
+}
+
+// NewAuditLog builds a chronological audit log of the votes, reviewer updates,
+// topic and hashtag edits of change from its change messages.
+//
+// It is the fallback of GetAuditLog for servers without meta revisions.
+// The votes, topic and hashtag edits are taken from the English messages Gerrit posts for them,
+// so the log is only as reliable as these messages.
+// The change must have been retrieved with the MESSAGES and REVIEWER_UPDATES options.
+func NewAuditLog(change *ChangeInfo) []AuditEvent {
+	var events []AuditEvent
+	for _, m := range change.Messages {
+		events = append(events, auditMessageEvents(m)...)
+	}
+	for _, u := range change.ReviewerUpdates {
+		events = append(events, AuditEvent{
+			Kind:     AuditReviewer,
+			Date:     u.Updated,
+			Account:  u.UpdatedBy,
+			Reviewer: u.Reviewer,
+			State:    u.State,
+		})
+	}
+	sortAuditEvents(events)
+	return events
+}
+
+// sortAuditEvents sorts events by date, keeping the order of events with the same date.
+func sortAuditEvents(events []AuditEvent) {
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Date.Before(events[j].Date.Time)
+	})
+}
+
+// auditMessageEvents extracts the audit events of a change message.
+func auditMessageEvents(m ChangeMessageInfo) []AuditEvent {
+	base := AuditEvent{Date: m.Date, Account: m.Author, PatchSet: m.RevisionNumber}
+	if m.RealAuthor.AccountID != 0 && m.RealAuthor.AccountID != m.Author.AccountID {
+		realAuthor := m.RealAuthor
+		base.RealAccount = &realAuthor
+	}
+
+	firstLine := strings.TrimSpace(strings.SplitN(m.Message, "\n", 2)[0])
+	var events []AuditEvent
+	add := func(e AuditEvent) {
+		events = append(events, e)
+	}
+
+	if p := auditPatchSetVotesPattern.FindStringSubmatch(firstLine); p != nil {
+		for _, token := range strings.Fields(p[1]) {
+			v := auditVotePattern.FindStringSubmatch(token)
+			if v == nil || (v[1] == "" && v[3] == "") || (v[1] != "" && v[3] != "") {
+				// Not a vote, e.g. "Cherry Picked" or "(2 comments)".
+				continue
+			}
+			e := base
+			e.Kind, e.Label, e.Voter = AuditLabel, v[2], m.Author
+			if v[3] != "" {
+				value, err := ParseLabelValue(v[3])
+				if err != nil {
+					continue
+				}
+				e.Value = value
+			}
+			add(e)
+		}
+		return events
+	}
+
+	switch {
+	case auditRemovedVotePattern.MatchString(firstLine):
+		r := auditRemovedVotePattern.FindStringSubmatch(firstLine)
+		e := base
+		e.Kind, e.Label = AuditLabel, r[1]
+		if addr, err := mail.ParseAddress(r[2]); err == nil {
+			e.Voter.Name, e.Voter.Email = addr.Name, addr.Address
+		} else {
+			e.Voter.Name = r[2]
+		}
+		add(e)
+	case auditTopicChangedPattern.MatchString(firstLine):
+		t := auditTopicChangedPattern.FindStringSubmatch(firstLine)
+		e := base
+		e.Kind, e.Old, e.New = AuditTopic, t[1], t[2]
+		add(e)
+	case auditTopicSetPattern.MatchString(firstLine):
+		e := base
+		e.Kind, e.New = AuditTopic, auditTopicSetPattern.FindStringSubmatch(firstLine)[1]
+		add(e)
+	case auditTopicRemovedPattern.MatchString(firstLine):
+		e := base
+		e.Kind, e.Old = AuditTopic, auditTopicRemovedPattern.FindStringSubmatch(firstLine)[1]
+		add(e)
+	case auditHashtagsPattern.MatchString(firstLine):
+		h := auditHashtagsPattern.FindStringSubmatch(firstLine)
+		e := base
+		e.Kind = AuditHashtags
+		tags := strings.Split(h[2], ", ")
+		if h[1] == "added" {
+			e.Added = tags
+		} else {
+			e.Removed = tags
+		}
+		add(e)
+	}
+	return events
+}
+
+// AuditEvents converts the difference between two successive meta revisions into audit events.
+// The ChangeInfo entities should contain the fields requested by the MESSAGES, DETAILED_ACCOUNTS,
+// DETAILED_LABELS and REVIEWER_UPDATES options.
+//
+// The account that performed the update is taken from the change message or reviewer update
+// that was added with it. If neither exists, votes are attributed to the voter and the account
+// of other events is unknown.
+// Events are dated with the update time of the newer meta revision, or the date of the vote.
+func (d *ChangeInfoDifference) AuditEvents() []AuditEvent {
+	var events []AuditEvent
+	base := AuditEvent{Date: d.Added.Updated}
+	actorKnown := false
+	if len(d.Added.Messages) > 0 {
+		m := d.Added.Messages[0]
+		base.Account, base.PatchSet, actorKnown = m.Author, m.RevisionNumber, true
+		if m.RealAuthor.AccountID != 0 && m.RealAuthor.AccountID != m.Author.AccountID {
+			realAuthor := m.RealAuthor
+			base.RealAccount = &realAuthor
+		}
+		if base.Date.IsZero() {
+			base.Date = m.Date
+		}
+	} else if len(d.Added.ReviewerUpdates) > 0 {
+		u := d.Added.ReviewerUpdates[0]
+		base.Account, actorKnown = u.UpdatedBy, true
+		if base.Date.IsZero() {
+			base.Date = u.Updated
+		}
+	}
+
+	labels := make([]string, 0, len(d.Added.Labels)+len(d.Removed.Labels))
+	seen := map[string]bool{}
+	for _, m := range []map[string]LabelInfo{d.Added.Labels, d.Removed.Labels} {
+		for name := range m {
+			if !seen[name] {
+				seen[name] = true
+				labels = append(labels, name)
+			}
+		}
+	}
+	sort.Strings(labels)
+	for _, name := range labels {
+		oldVotes := map[int]ApprovalInfo{}
+		for _, a := range d.Removed.Labels[name].All {
+			oldVotes[a.AccountID] = a
+		}
+		newVotes := map[int]bool{}
+		vote := func(voter AccountInfo, value int, date Timestamp) {
+			e := base
+			e.Kind, e.Label, e.Value, e.Voter = AuditLabel, name, value, voter
+			if !actorKnown {
+				e.Account = voter
+			}
+			if !date.IsZero() {
+				e.Date = date
+			}
+			events = append(events, e)
+		}
+		for _, a := range d.Added.Labels[name].All {
+			newVotes[a.AccountID] = true
+			if a.Value != oldVotes[a.AccountID].Value {
+				date := a.Date
+				if a.Value == 0 {
+					date = Timestamp{}
+				}
+				vote(a.AccountInfo, a.Value, date)
+			}
+		}
+		for _, a := range d.Removed.Labels[name].All {
+			if !newVotes[a.AccountID] && a.Value != 0 {
+				vote(a.AccountInfo, 0, Timestamp{})
+			}
+		}
+	}
+
+	for _, u := range d.Added.ReviewerUpdates {
+		events = append(events, AuditEvent{Kind: AuditReviewer, Date: u.Updated, Account: u.UpdatedBy, Reviewer: u.Reviewer, State: u.State})
+	}
+	if d.Added.Topic != d.Removed.Topic {
+		e := base
+		e.Kind, e.Old, e.New = AuditTopic, d.Removed.Topic, d.Added.Topic
+		events = append(events, e)
+	}
+	if len(d.Added.Hashtags) > 0 || len(d.Removed.Hashtags) > 0 {
+		e := base
+		e.Kind, e.Added, e.Removed = AuditHashtags, d.Added.Hashtags, d.Removed.Hashtags
+		events = append(events, e)
+	}
+	return events
+}
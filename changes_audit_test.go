@@ -0,0 +1,200 @@
+package gerrit_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/andygrunwald/go-gerrit"
+)
+
+func TestChangesService_GetChangeAtMetaRevision(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/changes/123", func(w http.ResponseWriter, r *http.Request) {
+		testFormValues(t, r, testValues{"meta": "8f4a0ccaa0e8d0c8a8d5f8d9d1b0c1a2e3f4a5b6"})
+		fmt.Fprint(w, `{"_number": 123, "topic": "old", "meta_rev_id": "8f4a0ccaa0e8d0c8a8d5f8d9d1b0c1a2e3f4a5b6"}`)
+	})
+
+	change, _, err := testClient.Changes.GetChange(context.Background(), "123", &gerrit.ChangeOptions{Meta: "8f4a0ccaa0e8d0c8a8d5f8d9d1b0c1a2e3f4a5b6"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if change.MetaRevID != "8f4a0ccaa0e8d0c8a8d5f8d9d1b0c1a2e3f4a5b6" || change.Topic != "old" {
+		t.Errorf("Unexpected change %+v", change)
+	}
+}
+
+func TestChangesService_GetMetaDiff(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/changes/123/meta_diff", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testFormValues(t, r, testValues{"old": "aaa", "meta": "bbb", "o": "DETAILED_LABELS"})
+		fmt.Fprint(w, `)]}'
+{
+  "added": {
+    "topic": "new",
+    "hashtags": ["release"],
+    "updated": "2024-03-01 10:00:00.000000000",
+    "labels": {"Code-Review": {"all": [{"_account_id": 1000, "value": 2, "date": "2024-03-01 09:59:00.000000000"}]}}
+  },
+  "removed": {
+    "topic": "old",
+    "labels": {"Verified": {"all": [{"_account_id": 1001, "value": 1}]}}
+  }
+}`)
+	})
+
+	diff, _, err := testClient.Changes.GetMetaDiff(context.Background(), "123", &gerrit.MetaDiffOptions{Old: "aaa", Meta: "bbb", AdditionalFields: []string{"DETAILED_LABELS"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff.Added.Topic != "new" || diff.Removed.Topic != "old" {
+		t.Fatalf("Unexpected diff %+v", diff)
+	}
+
+	var kinds []string
+	for _, e := range diff.AuditEvents() {
+		kinds = append(kinds, fmt.Sprintf("%s %s%+d %d %s>%s %v", e.Kind, e.Label, e.Value, e.Account.AccountID, e.Old, e.New, e.Added))
+	}
+	want := []string{
+		"LABEL Code-Review+2 1000 > []",
+		"LABEL Verified+0 1001 > []",
+		"TOPIC +0 0 old>new []",
+		"HASHTAGS +0 0 > [release]",
+	}
+	if !reflect.DeepEqual(kinds, want) {
+		t.Errorf("AuditEvents():\ngot:  %q\nwant: %q", kinds, want)
+	}
+}
+
+func TestChangesService_GetAuditLog_Messages(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/changes/123", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query()["o"]; !reflect.DeepEqual(got, []string{"MESSAGES", "DETAILED_ACCOUNTS", "DETAILED_LABELS", "REVIEWER_UPDATES"}) {
+			t.Errorf("Unexpected options %v", got)
+		}
+		fmt.Fprint(w, `{
+  "_number": 123,
+  "messages": [
+    {"id": "m1", "author": {"_account_id": 1000}, "date": "2024-03-01 10:00:00.000000000", "message": "Uploaded patch set 1.", "_revision_number": 1},
+    {"id": "m2", "author": {"_account_id": 1001}, "date": "2024-03-01 11:00:00.000000000", "message": "Patch Set 1: Code-Review-1 Verified+1\n\n(2 comments)", "_revision_number": 1},
+    {"id": "m3", "author": {"_account_id": 1000}, "date": "2024-03-01 12:00:00.000000000", "message": "Topic changed from old to new"},
+    {"id": "m4", "author": {"_account_id": 1001}, "date": "2024-03-01 13:00:00.000000000", "message": "Patch Set 2: -Code-Review\n", "_revision_number": 2},
+    {"id": "m5", "author": {"_account_id": 1000}, "real_author": {"_account_id": 1005}, "date": "2024-03-01 14:00:00.000000000", "message": "Hashtags added: a, b"},
+    {"id": "m6", "author": {"_account_id": 1002}, "date": "2024-03-01 15:00:00.000000000", "message": "Removed Verified+1 by Jane Roe <jane@example.com>\n"}
+  ],
+  "reviewer_updates": [
+    {"updated": "2024-03-01 10:30:00.000000000", "updated_by": {"_account_id": 1000}, "reviewer": {"_account_id": 1001}, "state": "REVIEWER"}
+  ]
+}`)
+	})
+
+	events, _, err := testClient.Changes.GetAuditLog(context.Background(), "123")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, e := range events {
+		s := fmt.Sprintf("%s by %d", e.Kind, e.Account.AccountID)
+		switch e.Kind {
+		case gerrit.AuditLabel:
+			s += fmt.Sprintf(": PS%d %s=%d", e.PatchSet, e.Label, e.Value)
+		case gerrit.AuditReviewer:
+			s += fmt.Sprintf(": %d %s", e.Reviewer.AccountID, e.State)
+		case gerrit.AuditTopic:
+			s += fmt.Sprintf(": %s -> %s", e.Old, e.New)
+		case gerrit.AuditHashtags:
+			s += fmt.Sprintf(": +%v on behalf of %d", e.Added, e.RealAccount.AccountID)
+		}
+		got = append(got, s)
+	}
+	want := []string{
+		"REVIEWER by 1000: 1001 REVIEWER",
+		"LABEL by 1001: PS1 Code-Review=-1",
+		"LABEL by 1001: PS1 Verified=1",
+		"TOPIC by 1000: old -> new",
+		"LABEL by 1001: PS2 Code-Review=0",
+		"HASHTAGS by 1000: +[a b] on behalf of 1005",
+		"LABEL by 1002: PS0 Verified=0",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAuditLog():\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
+func TestChangesService_GetAuditLog(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/changes/123", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"_number": 123, "meta_rev_id": "m3"}`)
+	})
+	diffs := map[string]string{
+		// Jane removes the vote of account 1001.
+		"m3": `{
+  "added": {
+    "meta_rev_id": "m3", "updated": "2024-03-01 15:00:00.000000000",
+    "messages": [{"id": "c3", "author": {"_account_id": 1002}, "date": "2024-03-01 15:00:00.000000000", "message": "Removed Verified+1 by Joe"}]
+  },
+  "removed": {
+    "meta_rev_id": "m2",
+    "labels": {"Verified": {"all": [{"_account_id": 1001, "value": 1}]}}
+  }
+}`,
+		// The owner changes the topic.
+		"m2": `{
+  "added": {"meta_rev_id": "m2", "updated": "2024-03-01 12:00:00.000000000", "topic": "new",
+    "messages": [{"id": "c2", "author": {"_account_id": 1000}, "date": "2024-03-01 12:00:00.000000000", "message": "Topic changed from old to new"}]},
+  "removed": {"meta_rev_id": "m1", "topic": "old"}
+}`,
+		// Account 1001 votes; the reviewer entry of account 1003 without vote is ignored.
+		"m1": `{
+  "added": {
+    "meta_rev_id": "m1", "updated": "2024-03-01 11:00:00.000000000",
+    "messages": [{"id": "c1", "author": {"_account_id": 1001}, "date": "2024-03-01 11:00:00.000000000", "message": "Patch Set 1: Verified+1", "_revision_number": 1}],
+    "labels": {"Verified": {"all": [{"_account_id": 1001, "value": 1, "date": "2024-03-01 11:00:00.000000000"}, {"_account_id": 1003}]}}
+  },
+  "removed": {}
+}`,
+	}
+	testMux.HandleFunc("/changes/123/meta_diff", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("old"); got != "" {
+			t.Errorf("Unexpected old %q", got)
+		}
+		fmt.Fprint(w, diffs[r.URL.Query().Get("meta")])
+	})
+
+	events, _, err := testClient.Changes.GetAuditLog(context.Background(), "123")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, e := range events {
+		s := fmt.Sprintf("%s by %d", e.Kind, e.Account.AccountID)
+		switch e.Kind {
+		case gerrit.AuditLabel:
+			s += fmt.Sprintf(": PS%d %s=%d of %d", e.PatchSet, e.Label, e.Value, e.Voter.AccountID)
+		case gerrit.AuditTopic:
+			s += fmt.Sprintf(": %s -> %s", e.Old, e.New)
+		}
+		got = append(got, s)
+	}
+	want := []string{
+		"LABEL by 1001: PS1 Verified=1 of 1001",
+		"TOPIC by 1000: old -> new",
+		"LABEL by 1002: PS0 Verified=0 of 1001",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAuditLog():\ngot:  %q\nwant: %q", got, want)
+	}
+}
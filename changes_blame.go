@@ -0,0 +1,98 @@
+package gerrit
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// BlameInfo entity stores the commit metadata with the row coordinates where it applies.
+type BlameInfo struct {
+	Author    string      `json:"author"`
+	ID        string      `json:"id"`
+	Time      int         `json:"time"`
+	CommitMsg string      `json:"commit_msg"`
+	Ranges    []RangeInfo `json:"ranges"`
+}
+
+// RangeInfo entity stores the coordinates of a range.
+// Start and End are 1-based line numbers, both inclusive.
+type RangeInfo struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// BlameOptions specifies the parameters for GetBlame call.
+type BlameOptions struct {
+	// If the base parameter is set, the blame information is computed for the parent commit
+	// of the revision instead of the revision itself.
+	Base bool `url:"base,omitempty"`
+}
+
+// GetBlame gets the blame information of a file of a revision.
+//
+// Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#get-blame
+func (s *ChangesService) GetBlame(ctx context.Context, changeID, revisionID, fileID string, opt *BlameOptions) ([]BlameInfo, *Response, error) {
+	u := fmt.Sprintf("changes/%s/revisions/%s/files/%s/blame", changeID, revisionID, url.PathEscape(fileID))
+
+	u, err := addOptions(u, opt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var v []BlameInfo
+	resp, err := s.client.Do(req, &v)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return v, resp, err
+}
+
+// BlamedLine is a changed line of a diff with the commit that last modified it.
+type BlamedLine struct {
+	// Line is the 1-based line number on the blamed side.
+	Line int
+	Text string
+	// Blame is the commit that last modified the line, or nil if the blame doesn't cover the line.
+	Blame *BlameInfo
+}
+
+// BlameDiffLines attributes the changed lines of diff on the given side to the commits that last modified them.
+//
+// With side "PARENT", the deleted and modified lines are attributed using the blame of
+// the parent, as returned by GetBlame with BlameOptions.Base set.
+// This tells who last touched the lines that the change modifies.
+// With side "REVISION", the added lines are attributed using the blame of the revision itself.
+func BlameDiffLines(diff *DiffInfo, blame []BlameInfo, side string) []BlamedLine {
+	byLine := map[int]*BlameInfo{}
+	for i := range blame {
+		for _, r := range blame[i].Ranges {
+			for line := r.Start; line <= r.End; line++ {
+				byLine[line] = &blame[i]
+			}
+		}
+	}
+
+	op, parent := byte('+'), side == "PARENT"
+	if parent {
+		op = '-'
+	}
+	var result []BlamedLine
+	for _, l := range diff.diffLines() {
+		if l.op != op {
+			continue
+		}
+		line := l.b
+		if parent {
+			line = l.a
+		}
+		result = append(result, BlamedLine{Line: line, Text: l.text, Blame: byLine[line]})
+	}
+	return result
+}
@@ -0,0 +1,62 @@
+package gerrit_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/andygrunwald/go-gerrit"
+)
+
+func TestChangesService_GetBlame(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/changes/123/revisions/2/files/src/main.go/blame", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testFormValues(t, r, testValues{"base": "true"})
+		fmt.Fprint(w, `)]}'
+[
+  {"author": "Jane Roe", "id": "aaaa", "time": 1433251200, "commit_msg": "Initial commit\n", "ranges": [{"start": 1, "end": 2}, {"start": 5, "end": 5}]},
+  {"author": "John Doe", "id": "bbbb", "time": 1433337600, "commit_msg": "Fix printing\n", "ranges": [{"start": 3, "end": 4}]}
+]`)
+	})
+
+	blame, _, err := testClient.Changes.GetBlame(context.Background(), "123", "2", "src/main.go", &gerrit.BlameOptions{Base: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blame) != 2 || blame[1].Author != "John Doe" || blame[0].Ranges[1].Start != 5 {
+		t.Fatalf("Unexpected blame %+v", blame)
+	}
+
+	var diff gerrit.DiffInfo
+	err = json.Unmarshal([]byte(`{
+  "change_type": "MODIFIED",
+  "content": [
+    {"ab": ["package main", ""]},
+    {"a": ["func main() {", "\tprintln(\"old\")"], "b": ["func main() {", "\tprintln(\"new\")", "\tprintln(\"more\")"]},
+    {"ab": ["}"]}
+  ]
+}`), &diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := gerrit.BlameDiffLines(&diff, blame, "PARENT")
+	if len(lines) != 2 {
+		t.Fatalf("Got %d blamed lines, want 2", len(lines))
+	}
+	for i, l := range lines {
+		if l.Line != i+3 || l.Blame == nil || l.Blame.ID != "bbbb" {
+			t.Errorf("Unexpected blamed line %+v", l)
+		}
+	}
+
+	lines = gerrit.BlameDiffLines(&diff, blame, "REVISION")
+	if len(lines) != 3 || lines[2].Line != 5 || lines[2].Text != "\tprintln(\"more\")" || lines[2].Blame.ID != "aaaa" {
+		t.Errorf("Unexpected blamed lines %+v", lines)
+	}
+}
@@ -0,0 +1,194 @@
+package gerrit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// defaultCherryPickParallelism is the number of concurrent cherry-picks of CherryPickToBranches
+// if CherryPickBranchesOptions.Parallelism is not set.
+const defaultCherryPickParallelism = 4
+
+// conflictFilesHeader introduces the list of files with conflict markers in the change message
+// Gerrit posts for cherry-picks and rebases with conflicts.
+const conflictFilesHeader = "The following files contain Git conflicts:"
+
+// CherryPickBranchesOptions specifies the parameters of CherryPickToBranches.
+type CherryPickBranchesOptions struct {
+	// CherryPickInput is used for every cherry-pick.
+	// Its Destination is set to each of the branches.
+	// If Message is empty, the commit message of the source revision is used.
+	// If Topic is empty, the topic of the source change is kept.
+	CherryPickInput
+
+	// Branches are the destination branches.
+	Branches []string
+
+	// Parallelism is the maximum number of cherry-picks running at the same time.
+	// It defaults to 4.
+	Parallelism int
+
+	// KeepHashtags adds the hashtags of the source change to the cherry-picks.
+	KeepHashtags bool
+
+	// AddCherryPickedFromFooter adds a "Cherry-picked-from" footer with the commit of
+	// the source revision to the commit message.
+	AddCherryPickedFromFooter bool
+
+	// ConflictsAsWorkInProgress marks cherry-picks that contain conflicts as work in progress.
+	// It only has an effect together with AllowConflicts.
+	ConflictsAsWorkInProgress bool
+}
+
+// CherryPickResult is the outcome of cherry-picking a revision to a single branch.
+type CherryPickResult struct {
+	Branch string
+	// Change is the created cherry-pick, if the cherry-pick succeeded.
+	Change *ChangeInfo
+	// Skipped is true if the branch already includes the change.
+	Skipped bool
+	// Conflicts is true if the cherry-pick was created with conflict markers.
+	Conflicts bool
+	// ConflictFiles are the files that contain conflict markers.
+	ConflictFiles []string
+	// Err is the error of the cherry-pick or one of the follow-up operations.
+	// Change may be set even if Err is set, e.g. if adding hashtags failed.
+	Err error
+}
+
+// CherryPickToBranches cherry-picks a revision to several branches using CherryPickRevision.
+//
+// Branches that already include the change according to GetIncludedIn, as well as
+// the branch of the change itself, are skipped.
+// Up to opt.Parallelism cherry-picks run at the same time.
+// A failure on one branch doesn't stop the others; the results are returned in the order of opt.Branches.
+// The returned error is only set if opt has no branches or the source change couldn't be retrieved.
+func (s *ChangesService) CherryPickToBranches(ctx context.Context, changeID, revisionID string, opt *CherryPickBranchesOptions) ([]CherryPickResult, error) {
+	if opt == nil || len(opt.Branches) == 0 {
+		return nil, errors.New("no branches to cherry-pick to")
+	}
+
+	change, _, err := s.GetChange(ctx, changeID, nil)
+	if err != nil {
+		return nil, err
+	}
+	commit, _, err := s.GetCommit(ctx, changeID, revisionID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	included := map[string]bool{change.Branch: true}
+	if change.Status == "MERGED" {
+		in, _, err := s.GetIncludedIn(ctx, changeID)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range in.Branches {
+			included[strings.TrimPrefix(b, "refs/heads/")] = true
+		}
+	}
+
+	template := opt.CherryPickInput
+	if template.Topic == "" {
+		template.Topic = change.Topic
+	}
+	if opt.AddCherryPickedFromFooter {
+		message := template.Message
+		if message == "" {
+			message = commit.Message
+		}
+		m := ParseCommitMessage(message)
+		m.SetFooter(FooterCherryPickedFrom, commit.Commit)
+		template.Message = m.String()
+	}
+
+	parallelism := opt.Parallelism
+	if parallelism <= 0 {
+		parallelism = defaultCherryPickParallelism
+	}
+	sem := make(chan struct{}, parallelism)
+	results := make([]CherryPickResult, len(opt.Branches))
+	var wg sync.WaitGroup
+	for i, branch := range opt.Branches {
+		results[i].Branch = branch
+		if included[strings.TrimPrefix(branch, "refs/heads/")] {
+			results[i].Skipped = true
+			continue
+		}
+
+		wg.Add(1)
+		go func(r *CherryPickResult) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			input := template
+			input.Destination = r.Branch
+			s.cherryPickToBranch(ctx, changeID, revisionID, &input, change.Hashtags, opt, r)
+		}(&results[i])
+	}
+	wg.Wait()
+	return results, nil
+}
+
+// cherryPickToBranch performs a single cherry-pick of CherryPickToBranches and its follow-up operations.
+func (s *ChangesService) cherryPickToBranch(ctx context.Context, changeID, revisionID string, input *CherryPickInput, hashtags []string, opt *CherryPickBranchesOptions, r *CherryPickResult) {
+	r.Change, _, r.Err = s.CherryPickRevision(ctx, changeID, revisionID, input)
+	if r.Err != nil {
+		r.Change = nil
+		r.Err = fmt.Errorf("cherry-picking to %s: %v", r.Branch, r.Err)
+		return
+	}
+	id := r.Change.ID
+
+	if opt.KeepHashtags && len(hashtags) > 0 {
+		if _, _, err := s.SetHashtags(ctx, id, &HashtagsInput{Add: hashtags}); err != nil {
+			r.Err = fmt.Errorf("adding hashtags to %s: %v", id, err)
+			return
+		}
+	}
+
+	if !r.Change.ContainsGitConflicts {
+		return
+	}
+	r.Conflicts = true
+	messages, _, err := s.ListChangeMessages(ctx, id)
+	if err != nil {
+		r.Err = fmt.Errorf("listing messages of %s: %v", id, err)
+		return
+	}
+	for _, m := range messages {
+		r.ConflictFiles = append(r.ConflictFiles, parseConflictFiles(m.Message)...)
+	}
+
+	if opt.ConflictsAsWorkInProgress && !r.Change.WorkInProgress {
+		if _, err := s.SetWorkInProgress(ctx, id, &WorkInProgressInput{Message: "Cherry-pick contains conflicts."}); err != nil {
+			r.Err = fmt.Errorf("marking %s as work in progress: %v", id, err)
+			return
+		}
+		r.Change.WorkInProgress = true
+	}
+}
+
+// parseConflictFiles extracts the files listed after conflictFilesHeader in a change message.
+func parseConflictFiles(message string) []string {
+	i := strings.Index(message, conflictFilesHeader)
+	if i < 0 {
+		return nil
+	}
+	var files []string
+	for _, line := range strings.Split(message[i+len(conflictFilesHeader):], "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" && len(files) == 0 {
+			continue
+		}
+		if !strings.HasPrefix(line, "* ") {
+			break
+		}
+		files = append(files, strings.TrimPrefix(line, "* "))
+	}
+	return files
+}
@@ -0,0 +1,113 @@
+package gerrit_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/andygrunwald/go-gerrit"
+)
+
+func TestChangesService_CherryPickToBranches(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/changes/123", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"_number": 123, "branch": "main", "status": "MERGED", "topic": "fix", "hashtags": ["backport"]}`)
+	})
+	testMux.HandleFunc("/changes/123/revisions/current/commit", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"commit": "674ac754f91e64a0efb8087e59a176484bd534d1", "message": "Fix the bug\n\nChange-Id: I8473b95934b5732ac55d26311a706c9c2bde9940\n"}`)
+	})
+	testMux.HandleFunc("/changes/123/in", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"branches": ["main", "release-2"], "tags": []}`)
+	})
+
+	var mu sync.Mutex
+	var picked []string
+	testMux.HandleFunc("/changes/123/revisions/current/cherrypick", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		var input gerrit.CherryPickInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			t.Error(err)
+		}
+		if input.Topic != "fix" {
+			t.Errorf("Topic = %q, want fix", input.Topic)
+		}
+		want := "Fix the bug\n\nChange-Id: I8473b95934b5732ac55d26311a706c9c2bde9940\nCherry-picked-from: 674ac754f91e64a0efb8087e59a176484bd534d1\n"
+		if input.Message != want {
+			t.Errorf("Message:\ngot:  %q\nwant: %q", input.Message, want)
+		}
+		mu.Lock()
+		picked = append(picked, input.Destination)
+		mu.Unlock()
+
+		switch input.Destination {
+		case "release-1":
+			fmt.Fprint(w, `{"id": "p~release-1~I1", "_number": 201}`)
+		case "release-3":
+			fmt.Fprint(w, `{"id": "p~release-3~I3", "_number": 203, "contains_git_conflicts": true}`)
+		default:
+			http.Error(w, "branch not found", http.StatusBadRequest)
+		}
+	})
+	for _, id := range []string{"p~release-1~I1", "p~release-3~I3"} {
+		testMux.HandleFunc("/changes/"+id+"/hashtags", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, "POST")
+			fmt.Fprint(w, `["backport"]`)
+		})
+	}
+	testMux.HandleFunc("/changes/p~release-3~I3/messages", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id": "1", "message": "Patch Set 1: Cherry Picked from branch main.\n\nThe following files contain Git conflicts:\n* a.go\n* b/c.go\n"}]`)
+	})
+	wip := false
+	testMux.HandleFunc("/changes/p~release-3~I3/wip", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		wip = true
+	})
+
+	results, err := testClient.Changes.CherryPickToBranches(context.Background(), "123", "current", &gerrit.CherryPickBranchesOptions{
+		CherryPickInput:           gerrit.CherryPickInput{AllowConflicts: true},
+		Branches:                  []string{"release-1", "release-2", "release-3", "release-4"},
+		Parallelism:               2,
+		KeepHashtags:              true,
+		AddCherryPickedFromFooter: true,
+		ConflictsAsWorkInProgress: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(picked) != 3 {
+		t.Errorf("Cherry-picked to %v, want 3 branches", picked)
+	}
+
+	if r := results[0]; r.Branch != "release-1" || r.Err != nil || r.Change.Number != 201 || r.Conflicts {
+		t.Errorf("Unexpected result for release-1: %+v", r)
+	}
+	if r := results[1]; !r.Skipped || r.Change != nil {
+		t.Errorf("release-2 should be skipped: %+v", r)
+	}
+	if r := results[2]; r.Err != nil || !r.Conflicts || !r.Change.WorkInProgress || !reflect.DeepEqual(r.ConflictFiles, []string{"a.go", "b/c.go"}) {
+		t.Errorf("Unexpected result for release-3: %+v", r)
+	}
+	if !wip {
+		t.Error("Conflicting cherry-pick was not marked as work in progress")
+	}
+	if r := results[3]; r.Err == nil || r.Change != nil {
+		t.Errorf("release-4 should have failed: %+v", r)
+	}
+}
+
+func TestChangesService_CherryPickToBranches_NoBranches(t *testing.T) {
+	setup()
+	defer teardown()
+
+	for _, opt := range []*gerrit.CherryPickBranchesOptions{nil, {}} {
+		if _, err := testClient.Changes.CherryPickToBranches(context.Background(), "123", "current", opt); err == nil {
+			t.Errorf("CherryPickToBranches(%+v) succeeded, want error", opt)
+		}
+	}
+}
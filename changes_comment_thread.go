@@ -0,0 +1,236 @@
+package gerrit
+
+import (
+	"sort"
+)
+
+// ThreadComment is a comment within a CommentThread.
+type ThreadComment struct {
+	CommentInfo
+
+	// Draft is true if the comment is an unpublished draft of the calling user.
+	Draft bool
+	// RobotID is set if the comment is a robot comment.
+	RobotID string
+}
+
+// CommentThread is a conversation of inline or file comments.
+// It consists of a root comment and all comments that reply to it,
+// directly or indirectly, via CommentInfo.InReplyTo.
+type CommentThread struct {
+	// Root is the comment that started the thread.
+	Root ThreadComment
+	// Replies are all other comments of the thread, ordered by their update time.
+	Replies []ThreadComment
+
+	// Path, PatchSet, Side, Line and Range describe where the thread is anchored.
+	// They are taken from the root comment.
+	Path     string
+	PatchSet int
+	Side     string
+	Line     int
+	Range    *CommentRange
+}
+
+// Comments returns the root comment followed by all replies.
+func (t *CommentThread) Comments() []ThreadComment {
+	return append([]ThreadComment{t.Root}, t.Replies...)
+}
+
+// last returns the last published comment of the thread.
+func (t *CommentThread) last() *ThreadComment {
+	for i := len(t.Replies) - 1; i >= 0; i-- {
+		if !t.Replies[i].Draft {
+			return &t.Replies[i]
+		}
+	}
+	if !t.Root.Draft {
+		return &t.Root
+	}
+	return nil
+}
+
+// Unresolved reports the effective resolved state of the thread.
+// Like in the Gerrit UI, a thread is unresolved if its last comment is unresolved.
+// Drafts are ignored, since they are not visible to other users.
+func (t *CommentThread) Unresolved() bool {
+	last := t.last()
+	return last != nil && last.Unresolved != nil && *last.Unresolved
+}
+
+// HasDrafts reports whether the calling user has unpublished drafts in the thread.
+func (t *CommentThread) HasDrafts() bool {
+	for _, c := range t.Comments() {
+		if c.Draft {
+			return true
+		}
+	}
+	return false
+}
+
+// LastAuthor returns the author of the last published comment of the thread.
+// The second return value is false if the thread consists of drafts only.
+func (t *CommentThread) LastAuthor() (AccountInfo, bool) {
+	last := t.last()
+	if last == nil {
+		return AccountInfo{}, false
+	}
+	return last.Author, true
+}
+
+// NewCommentThreads builds comment threads from the published comments,
+// the drafts and the robot comments of a change as returned by
+// ListChangeComments, ListChangeDrafts and ListChangeRobotComments.
+// Each of the maps may be nil.
+//
+// Comments whose parent is missing, e.g. because it was deleted, start a new thread.
+// Comments replying to each other in a cycle form a single thread whose root is the earliest of them.
+// Threads are sorted by path, patch set, line and the update time of their root comment.
+func NewCommentThreads(comments, drafts map[string][]CommentInfo, robotComments map[string][]RobotCommentInfo) []CommentThread {
+	var all []ThreadComment
+	add := func(path string, c ThreadComment) {
+		if c.Path == "" {
+			c.Path = path
+		}
+		all = append(all, c)
+	}
+	for path, list := range comments {
+		for _, c := range list {
+			add(path, ThreadComment{CommentInfo: c})
+		}
+	}
+	for path, list := range drafts {
+		for _, c := range list {
+			add(path, ThreadComment{CommentInfo: c, Draft: true})
+		}
+	}
+	for path, list := range robotComments {
+		for _, c := range list {
+			add(path, ThreadComment{CommentInfo: c.CommentInfo, RobotID: c.RobotID})
+		}
+	}
+
+	byID := make(map[string]int, len(all))
+	for i, c := range all {
+		byID[c.ID] = i
+	}
+
+	// rootOf follows the InReplyTo chain to the root comment.
+	// Missing parents end the chain. If the chain ends in a cycle,
+	// the earliest comment of the cycle is taken as root.
+	rootOf := func(i int) int {
+		chain := []int{i}
+		pos := map[int]int{i: 0}
+		for {
+			parent, ok := byID[all[i].InReplyTo]
+			if all[i].InReplyTo == "" || !ok {
+				return i
+			}
+			if p, seen := pos[parent]; seen {
+				root := parent
+				for _, c := range chain[p:] {
+					if commentBefore(all[c], all[root]) {
+						root = c
+					}
+				}
+				return root
+			}
+			pos[parent] = len(chain)
+			chain = append(chain, parent)
+			i = parent
+		}
+	}
+
+	threadByRoot := map[int]int{}
+	var threads []CommentThread
+	for i := range all {
+		if rootOf(i) != i {
+			continue
+		}
+		root := all[i]
+		threadByRoot[i] = len(threads)
+		threads = append(threads, CommentThread{
+			Root:     root,
+			Path:     root.Path,
+			PatchSet: root.PatchSet,
+			Side:     root.Side,
+			Line:     root.Line,
+			Range:    root.Range,
+		})
+	}
+	for i := range all {
+		if r := rootOf(i); r != i {
+			t := &threads[threadByRoot[r]]
+			t.Replies = append(t.Replies, all[i])
+		}
+	}
+
+	for i := range threads {
+		replies := threads[i].Replies
+		sort.SliceStable(replies, func(a, b int) bool {
+			return updatedBefore(replies[a].Updated, replies[b].Updated)
+		})
+	}
+	sort.SliceStable(threads, func(a, b int) bool {
+		ta, tb := threads[a], threads[b]
+		if ta.Path != tb.Path {
+			return ta.Path < tb.Path
+		}
+		if ta.PatchSet != tb.PatchSet {
+			return ta.PatchSet < tb.PatchSet
+		}
+		if ta.Line != tb.Line {
+			return ta.Line < tb.Line
+		}
+		return updatedBefore(ta.Root.Updated, tb.Root.Updated)
+	})
+	return threads
+}
+
+// commentBefore orders comments by their update time and their ID.
+func commentBefore(a, b ThreadComment) bool {
+	if updatedBefore(a.Updated, b.Updated) {
+		return true
+	}
+	if updatedBefore(b.Updated, a.Updated) {
+		return false
+	}
+	return a.ID < b.ID
+}
+
+// updatedBefore orders comments by update time. Comments without a timestamp sort last.
+func updatedBefore(a, b *Timestamp) bool {
+	switch {
+	case a == nil:
+		return false
+	case b == nil:
+		return true
+	default:
+		return a.Before(b.Time)
+	}
+}
+
+// UnresolvedThreadsOnPatchSet returns the unresolved threads anchored on the given patch set,
+// e.g. the current revision number of a change.
+func UnresolvedThreadsOnPatchSet(threads []CommentThread, patchSet int) []CommentThread {
+	var result []CommentThread
+	for _, t := range threads {
+		if t.PatchSet == patchSet && t.Unresolved() {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// ThreadsAwaitingReplyFrom returns the unresolved threads in which somebody other than
+// the given account spoke last, e.g. the threads awaiting a reply of the change owner.
+func ThreadsAwaitingReplyFrom(threads []CommentThread, accountID int) []CommentThread {
+	var result []CommentThread
+	for _, t := range threads {
+		author, ok := t.LastAuthor()
+		if ok && t.Unresolved() && author.AccountID != accountID {
+			result = append(result, t)
+		}
+	}
+	return result
+}
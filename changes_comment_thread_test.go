@@ -0,0 +1,149 @@
+package gerrit_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/andygrunwald/go-gerrit"
+)
+
+func TestNewCommentThreads(t *testing.T) {
+	const (
+		commentsJSON = `{
+  "gerrit-server/src/main/java/com/google/gerrit/server/project/RefControl.java": [
+    {
+      "id": "TvcXrmjM",
+      "patch_set": 1,
+      "line": 23,
+      "message": "[nit] trailing whitespace",
+      "unresolved": true,
+      "updated": "2013-02-26 15:40:43.986000000",
+      "author": {"_account_id": 1000096, "name": "John Doe"}
+    },
+    {
+      "id": "TveXwFiA",
+      "patch_set": 1,
+      "line": 23,
+      "in_reply_to": "TvcXrmjM",
+      "message": "Done",
+      "unresolved": false,
+      "updated": "2013-02-26 15:49:29.486000000",
+      "author": {"_account_id": 1000097, "name": "Jane Roe"}
+    },
+    {
+      "id": "Tv2XwFiB",
+      "patch_set": 2,
+      "line": 10,
+      "message": "Why is this needed?",
+      "unresolved": true,
+      "updated": "2013-02-27 10:00:00.000000000",
+      "author": {"_account_id": 1000096, "name": "John Doe"}
+    }
+  ]
+}`
+		draftsJSON = `{
+  "gerrit-server/src/main/java/com/google/gerrit/server/project/RefControl.java": [
+    {
+      "id": "Dr4ftAAA",
+      "patch_set": 2,
+      "line": 10,
+      "in_reply_to": "Tv2XwFiB",
+      "message": "Because of ...",
+      "unresolved": false,
+      "updated": "2013-02-27 11:00:00.000000000"
+    }
+  ]
+}`
+		robotJSON = `{
+  "main.c": [
+    {
+      "id": "R0b0tXYZ",
+      "robot_id": "importantChecker",
+      "robot_run_id": "1xe1a8e",
+      "patch_set": 2,
+      "line": 5,
+      "message": "Missing semicolon",
+      "unresolved": true,
+      "updated": "2013-02-27 09:00:00.000000000",
+      "author": {"_account_id": 1000099, "name": "Bot"}
+    }
+  ]
+}`
+	)
+	var comments, drafts map[string][]gerrit.CommentInfo
+	var robotComments map[string][]gerrit.RobotCommentInfo
+	for s, v := range map[string]interface{}{commentsJSON: &comments, draftsJSON: &drafts, robotJSON: &robotComments} {
+		if err := json.Unmarshal([]byte(s), v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	threads := gerrit.NewCommentThreads(comments, drafts, robotComments)
+	if len(threads) != 3 {
+		t.Fatalf("got %d threads, want 3: %+v", len(threads), threads)
+	}
+
+	resolved := threads[0]
+	if resolved.Root.ID != "TvcXrmjM" || len(resolved.Replies) != 1 || resolved.Unresolved() {
+		t.Errorf("Unexpected first thread %+v", resolved)
+	}
+	if resolved.Path != "gerrit-server/src/main/java/com/google/gerrit/server/project/RefControl.java" || resolved.Line != 23 {
+		t.Errorf("Unexpected anchor %s:%d", resolved.Path, resolved.Line)
+	}
+
+	withDraft := threads[1]
+	if withDraft.Root.ID != "Tv2XwFiB" || !withDraft.HasDrafts() || !withDraft.Unresolved() {
+		t.Errorf("Unexpected second thread %+v", withDraft)
+	}
+	if author, ok := withDraft.LastAuthor(); !ok || author.AccountID != 1000096 {
+		t.Errorf("LastAuthor() = %+v, %t", author, ok)
+	}
+
+	robot := threads[2]
+	if robot.Path != "main.c" || robot.Root.RobotID != "importantChecker" {
+		t.Errorf("Unexpected robot thread %+v", robot)
+	}
+
+	unresolved := gerrit.UnresolvedThreadsOnPatchSet(threads, 2)
+	if len(unresolved) != 2 {
+		t.Errorf("UnresolvedThreadsOnPatchSet() returned %d threads, want 2", len(unresolved))
+	}
+
+	awaiting := gerrit.ThreadsAwaitingReplyFrom(threads, 1000099)
+	if len(awaiting) != 1 || awaiting[0].Root.ID != "Tv2XwFiB" {
+		t.Errorf("ThreadsAwaitingReplyFrom() = %+v", awaiting)
+	}
+}
+
+func TestNewCommentThreads_MissingParent(t *testing.T) {
+	comments := map[string][]gerrit.CommentInfo{
+		"a.go": {
+			{ID: "2", InReplyTo: "deleted", Message: "orphan"},
+			{ID: "3", InReplyTo: "2", Message: "reply"},
+		},
+	}
+	threads := gerrit.NewCommentThreads(comments, nil, nil)
+	if len(threads) != 1 || threads[0].Root.ID != "2" || len(threads[0].Replies) != 1 {
+		t.Errorf("Unexpected threads %+v", threads)
+	}
+	if threads[0].Path != "a.go" {
+		t.Errorf("Path = %q, want %q", threads[0].Path, "a.go")
+	}
+}
+
+func TestNewCommentThreads_Cycle(t *testing.T) {
+	comments := map[string][]gerrit.CommentInfo{
+		"a.go": {
+			{ID: "b", InReplyTo: "a", Message: "second"},
+			{ID: "a", InReplyTo: "b", Message: "first"},
+			{ID: "c", InReplyTo: "b", Message: "reply"},
+		},
+	}
+	threads := gerrit.NewCommentThreads(comments, nil, nil)
+	if len(threads) != 1 {
+		t.Fatalf("Unexpected threads %+v", threads)
+	}
+	if threads[0].Root.ID != "a" || len(threads[0].Replies) != 2 {
+		t.Errorf("Unexpected thread %+v", threads[0])
+	}
+}
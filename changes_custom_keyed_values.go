@@ -0,0 +1,69 @@
+package gerrit
+
+import (
+	"context"
+	"fmt"
+)
+
+// GetCustomKeyedValues gets the custom keyed values associated with a change.
+//
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#get-custom-keyed-values
+func (s *ChangesService) GetCustomKeyedValues(ctx context.Context, changeID string) (map[string]string, *Response, error) {
+	u := fmt.Sprintf("changes/%s/custom_keyed_values", changeID)
+
+	req, err := s.client.NewRequest(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var v map[string]string
+	resp, err := s.client.Do(req, &v)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return v, resp, err
+}
+
+// CustomKeyedValuesInput entity contains information about custom keyed values to add to, and/or remove from, a change.
+//
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#custom-keyed-values-input
+type CustomKeyedValuesInput struct {
+	// The map of custom keyed values to be added to the change.
+	// Existing values of the same keys are replaced.
+	Add map[string]string `json:"add,omitempty"`
+
+	// The list of keys of the custom keyed values to be removed from the change.
+	Remove []string `json:"remove,omitempty"`
+}
+
+// SetCustomKeyedValues adds and/or removes custom keyed values from a change.
+//
+// As response the change’s custom keyed values are returned as a map.
+//
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#set-custom-keyed-values
+func (s *ChangesService) SetCustomKeyedValues(ctx context.Context, changeID string, input *CustomKeyedValuesInput) (map[string]string, *Response, error) {
+	u := fmt.Sprintf("changes/%s/custom_keyed_values", changeID)
+
+	req, err := s.client.NewRequest(ctx, "POST", u, input)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var v map[string]string
+	resp, err := s.client.Do(req, &v)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return v, resp, err
+}
+
+// DeleteCustomKeyedValues removes the custom keyed values with the given keys from a change.
+//
+// As response the change’s remaining custom keyed values are returned as a map.
+//
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#set-custom-keyed-values
+func (s *ChangesService) DeleteCustomKeyedValues(ctx context.Context, changeID string, keys ...string) (map[string]string, *Response, error) {
+	return s.SetCustomKeyedValues(ctx, changeID, &CustomKeyedValuesInput{Remove: keys})
+}
@@ -0,0 +1,94 @@
+package gerrit_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/andygrunwald/go-gerrit"
+)
+
+func TestChangesService_GetCustomKeyedValues(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		const (
+			method = "GET"
+			path   = "/changes/123/custom_keyed_values"
+		)
+		if r.Method != method {
+			t.Errorf("Method %q != %q", r.Method, method)
+		}
+		if r.URL.Path != path {
+			t.Errorf("Path %q != %q", r.URL.Path, path)
+		}
+		_, err := fmt.Fprintf(w, `)]}'
+{
+  "build-id": "4711",
+  "artifacts": "https://ci.example.com/4711"
+}
+`)
+		if err != nil {
+			t.Error(err)
+		}
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	client := newClient(ctx, t, ts)
+
+	values, _, err := client.Changes.GetCustomKeyedValues(ctx, "123")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{"build-id": "4711", "artifacts": "https://ci.example.com/4711"}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("Unexpected custom keyed values %+v", values)
+	}
+}
+
+func TestChangesService_DeleteCustomKeyedValues(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		const (
+			method = "POST"
+			path   = "/changes/123/custom_keyed_values"
+		)
+		if r.Method != method {
+			t.Errorf("Method %q != %q", r.Method, method)
+		}
+		if r.URL.Path != path {
+			t.Errorf("Path %q != %q", r.URL.Path, path)
+		}
+		var input gerrit.CustomKeyedValuesInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			t.Error(err)
+		}
+		if want := (gerrit.CustomKeyedValuesInput{Remove: []string{"build-id"}}); !reflect.DeepEqual(input, want) {
+			t.Errorf("Unexpected input %+v", input)
+		}
+		_, err := fmt.Fprintf(w, `)]}'
+{
+  "artifacts": "https://ci.example.com/4711"
+}
+`)
+		if err != nil {
+			t.Error(err)
+		}
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	client := newClient(ctx, t, ts)
+
+	values, _, err := client.Changes.DeleteCustomKeyedValues(ctx, "123", "build-id")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(values) != 1 || values["artifacts"] != "https://ci.example.com/4711" {
+		t.Errorf("Unexpected custom keyed values %+v", values)
+	}
+}
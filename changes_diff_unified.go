@@ -0,0 +1,256 @@
+package gerrit
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrBinaryDiff is returned when a DiffInfo of a binary file is applied.
+var ErrBinaryDiff = errors.New("diff of a binary file has no content")
+
+// defaultUnifiedDiffContext is the number of context lines of UnifiedDiff if no options are given.
+const defaultUnifiedDiffContext = 3
+
+// UnifiedDiffOptions specifies the parameters of DiffInfo.UnifiedDiff.
+type UnifiedDiffOptions struct {
+	// Context is the number of unchanged lines shown around each change.
+	// Context can't extend into regions Gerrit skipped, see DiffOptions.Context.
+	Context int
+}
+
+// diffLine is a single line of a DiffInfo, or a skipped region.
+type diffLine struct {
+	// op is ' ' for common lines, '-' for deleted lines, '+' for added lines
+	// and 's' for skipped regions.
+	op   byte
+	text string
+	// skip is the number of lines of a skipped region.
+	skip int
+	// a and b are the 1-based line numbers of the line on both sides.
+	a, b int
+}
+
+// diffLines flattens the content of d.
+func (d *DiffInfo) diffLines() []diffLine {
+	var lines []diffLine
+	a, b := 1, 1
+	for _, c := range d.Content {
+		if c.Skip > 0 {
+			lines = append(lines, diffLine{op: 's', skip: c.Skip, a: a, b: b})
+			a += c.Skip
+			b += c.Skip
+		}
+		for _, text := range c.AB {
+			lines = append(lines, diffLine{op: ' ', text: text, a: a, b: b})
+			a++
+			b++
+		}
+		for _, text := range c.A {
+			lines = append(lines, diffLine{op: '-', text: text, a: a, b: b})
+			a++
+		}
+		for _, text := range c.B {
+			lines = append(lines, diffLine{op: '+', text: text, a: a, b: b})
+			b++
+		}
+	}
+	return lines
+}
+
+// diffPaths returns the old and new path of the file of d.
+func (d *DiffInfo) diffPaths() (string, string) {
+	oldPath, newPath := d.MetaA.Name, d.MetaB.Name
+	if oldPath == "" {
+		oldPath = newPath
+	}
+	if newPath == "" {
+		newPath = oldPath
+	}
+	return oldPath, newPath
+}
+
+// UnifiedDiff renders d as a unified diff in the format of "git diff".
+//
+// Added and deleted files are diffed against /dev/null, renames and copies are reported
+// with "rename from/to" or "copy from/to" lines, and binary files with a "Binary files differ" line.
+// File modes and object ids are not part of DiffInfo and are therefore omitted.
+// All lines are assumed to be terminated by a newline.
+// If opt is nil, three lines of context are shown.
+func (d *DiffInfo) UnifiedDiff(opt *UnifiedDiffOptions) string {
+	context := defaultUnifiedDiffContext
+	if opt != nil && opt.Context >= 0 {
+		context = opt.Context
+	}
+	oldPath, newPath := d.diffPaths()
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "diff --git a/%s b/%s\n", oldPath, newPath)
+	fromFile, toFile := "a/"+oldPath, "b/"+newPath
+	switch d.ChangeType {
+	case "ADDED":
+		fromFile = "/dev/null"
+	case "DELETED":
+		toFile = "/dev/null"
+	case "RENAMED":
+		fmt.Fprintf(&sb, "rename from %s\nrename to %s\n", oldPath, newPath)
+	case "COPIED":
+		fmt.Fprintf(&sb, "copy from %s\ncopy to %s\n", oldPath, newPath)
+	}
+	if d.Binary {
+		fmt.Fprintf(&sb, "Binary files %s and %s differ\n", fromFile, toFile)
+		return sb.String()
+	}
+
+	lines := d.diffLines()
+	hunks := unifiedHunks(lines, context)
+	if len(hunks) == 0 {
+		return sb.String()
+	}
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromFile, toFile)
+	for _, h := range hunks {
+		writeUnifiedHunk(&sb, lines[h[0]:h[1]])
+	}
+	return sb.String()
+}
+
+// unifiedHunks groups lines into hunks with the given number of context lines.
+// Each hunk is returned as a [start, end) range of indices of lines.
+func unifiedHunks(lines []diffLine, context int) [][2]int {
+	var hunks [][2]int
+	prevEnd := 0
+	for i := 0; i < len(lines); i++ {
+		if lines[i].op != '-' && lines[i].op != '+' {
+			continue
+		}
+
+		// Leading context, which must not overlap the previous hunk or cross a skipped region.
+		start := i
+		for start > prevEnd && i-start < context && lines[start-1].op == ' ' {
+			start--
+		}
+
+		// Extend the hunk over all changes that are at most 2*context common lines apart.
+		last := i
+		j := i + 1
+		for j < len(lines) {
+			switch lines[j].op {
+			case '-', '+':
+				last = j
+				j++
+				continue
+			case ' ':
+				k := j
+				for k < len(lines) && lines[k].op == ' ' {
+					k++
+				}
+				if k < len(lines) && lines[k].op != 's' && k-j <= 2*context {
+					j = k
+					continue
+				}
+			}
+			break
+		}
+
+		// Trailing context.
+		end := last + 1
+		for end < len(lines) && end-last-1 < context && lines[end].op == ' ' {
+			end++
+		}
+		hunks = append(hunks, [2]int{start, end})
+		prevEnd = end
+		i = end - 1
+	}
+	return hunks
+}
+
+// writeUnifiedHunk writes the header and lines of a hunk.
+func writeUnifiedHunk(sb *strings.Builder, lines []diffLine) {
+	countA, countB := 0, 0
+	for _, l := range lines {
+		if l.op != '+' {
+			countA++
+		}
+		if l.op != '-' {
+			countB++
+		}
+	}
+	fmt.Fprintf(sb, "@@ -%s +%s @@\n", hunkRange(lines[0].a, countA), hunkRange(lines[0].b, countB))
+	for _, l := range lines {
+		sb.WriteByte(l.op)
+		sb.WriteString(l.text)
+		sb.WriteByte('\n')
+	}
+}
+
+// hunkRange formats the range of a hunk header like git does.
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start-1)
+	case 1:
+		return fmt.Sprintf("%d", start)
+	default:
+		return fmt.Sprintf("%d,%d", start, count)
+	}
+}
+
+// Apply applies d to base, the content of the file on side A, and returns the content on side B.
+//
+// The lines of base that d contains are verified, so an error is returned if base doesn't match d.
+// The diff must therefore have been retrieved without ignoring whitespace.
+// The result ends with a newline if base does or base is empty.
+func (d *DiffInfo) Apply(base string) (string, error) {
+	if d.Binary {
+		return "", ErrBinaryDiff
+	}
+
+	trailingNewline := base == "" || strings.HasSuffix(base, "\n")
+	var baseLines []string
+	if base != "" {
+		baseLines = strings.Split(strings.TrimSuffix(base, "\n"), "\n")
+	}
+
+	var result []string
+	pos := 0
+	take := func(expected string, line int) error {
+		if pos >= len(baseLines) {
+			return fmt.Errorf("line %d: base content has only %d lines", line, len(baseLines))
+		}
+		if baseLines[pos] != expected {
+			return fmt.Errorf("line %d: base content %q doesn't match %q", line, baseLines[pos], expected)
+		}
+		pos++
+		return nil
+	}
+	for _, l := range d.diffLines() {
+		switch l.op {
+		case 's':
+			if pos+l.skip > len(baseLines) {
+				return "", fmt.Errorf("line %d: base content has only %d lines", l.a+l.skip-1, len(baseLines))
+			}
+			result = append(result, baseLines[pos:pos+l.skip]...)
+			pos += l.skip
+		case ' ':
+			if err := take(l.text, l.a); err != nil {
+				return "", err
+			}
+			result = append(result, l.text)
+		case '-':
+			if err := take(l.text, l.a); err != nil {
+				return "", err
+			}
+		case '+':
+			result = append(result, l.text)
+		}
+	}
+	if pos != len(baseLines) {
+		return "", fmt.Errorf("base content has %d lines, diff covers only %d", len(baseLines), pos)
+	}
+
+	s := strings.Join(result, "\n")
+	if trailingNewline && len(result) > 0 {
+		s += "\n"
+	}
+	return s, nil
+}
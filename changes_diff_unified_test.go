@@ -0,0 +1,221 @@
+package gerrit_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/andygrunwald/go-gerrit"
+)
+
+// unifiedDiffTests are DiffInfo payloads as returned by Gerrit with the expected unified diff
+// for a context of 3 lines, and the content of both sides where the diff can be applied.
+var unifiedDiffTests = []struct {
+	name    string
+	payload string
+	diff    string
+	a, b    string
+}{
+	{
+		name: "modified with skip",
+		payload: `{
+  "meta_a": {"name": "gerrit-server/src/main/java/com/google/gerrit/server/project/RefControl.java", "content_type": "text/x-java-source", "lines": 16},
+  "meta_b": {"name": "gerrit-server/src/main/java/com/google/gerrit/server/project/RefControl.java", "content_type": "text/x-java-source", "lines": 17},
+  "change_type": "MODIFIED",
+  "diff_header": ["diff --git a/RefControl.java b/RefControl.java"],
+  "content": [
+    {"skip": 4},
+    {"ab": ["import com.google.gerrit.common.data.Permission;", "import com.google.gerrit.common.data.PermissionRange;", "import com.google.gerrit.common.data.PermissionRule;", "import com.google.gerrit.reviewdb.Account;"]},
+    {"a": ["import com.google.gerrit.reviewdb.Branch;"], "b": ["import com.google.gerrit.reviewdb.AccountGroup;", "import com.google.gerrit.reviewdb.Branch;"]},
+    {"ab": ["import com.google.gerrit.reviewdb.Change;", "import com.google.gerrit.reviewdb.Project;", "import com.google.gerrit.server.CurrentUser;", "import com.google.gerrit.server.IdentifiedUser;", "import com.google.gerrit.server.git.GitRepositoryManager;", "import com.google.inject.Inject;", "import com.google.inject.assistedinject.Assisted;"]}
+  ]
+}`,
+		diff: `diff --git a/gerrit-server/src/main/java/com/google/gerrit/server/project/RefControl.java b/gerrit-server/src/main/java/com/google/gerrit/server/project/RefControl.java
+--- a/gerrit-server/src/main/java/com/google/gerrit/server/project/RefControl.java
++++ b/gerrit-server/src/main/java/com/google/gerrit/server/project/RefControl.java
+@@ -6,7 +6,8 @@
+ import com.google.gerrit.common.data.PermissionRange;
+ import com.google.gerrit.common.data.PermissionRule;
+ import com.google.gerrit.reviewdb.Account;
+-import com.google.gerrit.reviewdb.Branch;
++import com.google.gerrit.reviewdb.AccountGroup;
++import com.google.gerrit.reviewdb.Branch;
+ import com.google.gerrit.reviewdb.Change;
+ import com.google.gerrit.reviewdb.Project;
+ import com.google.gerrit.server.CurrentUser;
+`,
+		a: "package com.google.gerrit.server.project;\n\n// Copyright\n\n" +
+			"import com.google.gerrit.common.data.Permission;\nimport com.google.gerrit.common.data.PermissionRange;\nimport com.google.gerrit.common.data.PermissionRule;\nimport com.google.gerrit.reviewdb.Account;\n" +
+			"import com.google.gerrit.reviewdb.Branch;\n" +
+			"import com.google.gerrit.reviewdb.Change;\nimport com.google.gerrit.reviewdb.Project;\nimport com.google.gerrit.server.CurrentUser;\nimport com.google.gerrit.server.IdentifiedUser;\nimport com.google.gerrit.server.git.GitRepositoryManager;\nimport com.google.inject.Inject;\nimport com.google.inject.assistedinject.Assisted;\n",
+		b: "package com.google.gerrit.server.project;\n\n// Copyright\n\n" +
+			"import com.google.gerrit.common.data.Permission;\nimport com.google.gerrit.common.data.PermissionRange;\nimport com.google.gerrit.common.data.PermissionRule;\nimport com.google.gerrit.reviewdb.Account;\n" +
+			"import com.google.gerrit.reviewdb.AccountGroup;\nimport com.google.gerrit.reviewdb.Branch;\n" +
+			"import com.google.gerrit.reviewdb.Change;\nimport com.google.gerrit.reviewdb.Project;\nimport com.google.gerrit.server.CurrentUser;\nimport com.google.gerrit.server.IdentifiedUser;\nimport com.google.gerrit.server.git.GitRepositoryManager;\nimport com.google.inject.Inject;\nimport com.google.inject.assistedinject.Assisted;\n",
+	},
+	{
+		name: "two hunks",
+		payload: `{
+  "meta_a": {"name": "a.txt", "lines": 12},
+  "meta_b": {"name": "a.txt", "lines": 11},
+  "change_type": "MODIFIED",
+  "content": [
+    {"a": ["1"]},
+    {"ab": ["2", "3", "4", "5", "6", "7", "8", "9", "10"]},
+    {"a": ["11"], "b": ["eleven"]},
+    {"ab": ["12"]}
+  ]
+}`,
+		diff: `diff --git a/a.txt b/a.txt
+--- a/a.txt
++++ b/a.txt
+@@ -1,4 +1,3 @@
+-1
+ 2
+ 3
+ 4
+@@ -8,5 +7,5 @@
+ 8
+ 9
+ 10
+-11
++eleven
+ 12
+`,
+		a: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n",
+		b: "2\n3\n4\n5\n6\n7\n8\n9\n10\neleven\n12\n",
+	},
+	{
+		name: "added",
+		payload: `{
+  "meta_b": {"name": "new.txt", "lines": 2},
+  "change_type": "ADDED",
+  "content": [{"b": ["hello", "world"]}]
+}`,
+		diff: `diff --git a/new.txt b/new.txt
+--- /dev/null
++++ b/new.txt
+@@ -0,0 +1,2 @@
++hello
++world
+`,
+		a: "",
+		b: "hello\nworld\n",
+	},
+	{
+		name: "deleted",
+		payload: `{
+  "meta_a": {"name": "old.txt", "lines": 1},
+  "change_type": "DELETED",
+  "content": [{"a": ["bye"]}]
+}`,
+		diff: `diff --git a/old.txt b/old.txt
+--- a/old.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-bye
+`,
+		a: "bye\n",
+		b: "",
+	},
+	{
+		name: "pure rename",
+		payload: `{
+  "meta_a": {"name": "docs/old.md", "lines": 2},
+  "meta_b": {"name": "docs/new.md", "lines": 2},
+  "change_type": "RENAMED",
+  "content": [{"ab": ["# Title", "text"]}]
+}`,
+		diff: `diff --git a/docs/old.md b/docs/new.md
+rename from docs/old.md
+rename to docs/new.md
+`,
+		a: "# Title\ntext\n",
+		b: "# Title\ntext\n",
+	},
+	{
+		name: "binary",
+		payload: `{
+  "meta_a": {"name": "logo.png", "content_type": "image/png"},
+  "meta_b": {"name": "logo.png", "content_type": "image/png"},
+  "change_type": "MODIFIED",
+  "content": [],
+  "binary": true
+}`,
+		diff: `diff --git a/logo.png b/logo.png
+Binary files a/logo.png and b/logo.png differ
+`,
+	},
+}
+
+func TestDiffInfo_UnifiedDiff(t *testing.T) {
+	for _, tt := range unifiedDiffTests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diff gerrit.DiffInfo
+			if err := json.Unmarshal([]byte(tt.payload), &diff); err != nil {
+				t.Fatal(err)
+			}
+			if got := diff.UnifiedDiff(nil); got != tt.diff {
+				t.Errorf("UnifiedDiff():\ngot:\n%s\nwant:\n%s", got, tt.diff)
+			}
+		})
+	}
+}
+
+func TestDiffInfo_UnifiedDiffContext(t *testing.T) {
+	var diff gerrit.DiffInfo
+	if err := json.Unmarshal([]byte(unifiedDiffTests[1].payload), &diff); err != nil {
+		t.Fatal(err)
+	}
+	want := `diff --git a/a.txt b/a.txt
+--- a/a.txt
++++ b/a.txt
+@@ -1 +0,0 @@
+-1
+@@ -11 +10 @@
+-11
++eleven
+`
+	if got := diff.UnifiedDiff(&gerrit.UnifiedDiffOptions{Context: 0}); got != want {
+		t.Errorf("UnifiedDiff():\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDiffInfo_Apply(t *testing.T) {
+	for _, tt := range unifiedDiffTests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diff gerrit.DiffInfo
+			if err := json.Unmarshal([]byte(tt.payload), &diff); err != nil {
+				t.Fatal(err)
+			}
+			got, err := diff.Apply(tt.a)
+			if diff.Binary {
+				if err != gerrit.ErrBinaryDiff {
+					t.Errorf("Apply() error = %v, want %v", err, gerrit.ErrBinaryDiff)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.b {
+				t.Errorf("Apply():\ngot:  %q\nwant: %q", got, tt.b)
+			}
+		})
+	}
+}
+
+func TestDiffInfo_ApplyMismatch(t *testing.T) {
+	var diff gerrit.DiffInfo
+	if err := json.Unmarshal([]byte(unifiedDiffTests[1].payload), &diff); err != nil {
+		t.Fatal(err)
+	}
+	for _, base := range []string{
+		"1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n",
+		"1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n",
+		"1\n2\n3\n4\n5\n6\n7\n8\n9\n10\nXI\n12\n",
+	} {
+		if _, err := diff.Apply(base); err == nil {
+			t.Errorf("Apply(%q) succeeded, want error", base)
+		}
+	}
+}
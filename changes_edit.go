@@ -8,10 +8,11 @@ import (
 
 // EditInfo entity contains information about a change edit.
 type EditInfo struct {
-	Commit       CommitInfo           `json:"commit"`
-	BaseRevision string               `json:"baseRevision"`
-	Fetch        map[string]FetchInfo `json:"fetch"`
-	Files        map[string]FileInfo  `json:"files,omitempty"`
+	Commit             CommitInfo           `json:"commit"`
+	BaseRevision       string               `json:"base_revision"`
+	BasePatchSetNumber int                  `json:"base_patch_set_number,omitempty"`
+	Fetch              map[string]FetchInfo `json:"fetch"`
+	Files              map[string]FileInfo  `json:"files,omitempty"`
 }
 
 // EditFileInfo entity contains additional information of a file within a change edit.
@@ -226,7 +227,34 @@ func (s *ChangesService) RetrieveFileContentTypeFromChangeEdit(ctx context.Conte
 	return s.client.Do(req, nil)
 }
 
-/*
-Missing Change Edit Endpoints
-	Restore file content or rename files in Change Edit
-*/
+// RenameFileInChangeEdit renames a file in a change edit.
+//
+// When change edit doesn’t exist for this change yet it is created.
+// As response “204 No Content” is returned.
+//
+// Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#post-edit
+func (s *ChangesService) RenameFileInChangeEdit(ctx context.Context, changeID, oldPath, newPath string) (*Response, error) {
+	return s.postChangeEdit(ctx, changeID, &ChangeEditInput{OldPath: oldPath, NewPath: newPath})
+}
+
+// RestoreFileInChangeEdit restores the content of a file in a change edit
+// to the content of the patch set the edit is based on.
+//
+// When change edit doesn’t exist for this change yet it is created.
+// As response “204 No Content” is returned.
+//
+// Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#post-edit
+func (s *ChangesService) RestoreFileInChangeEdit(ctx context.Context, changeID, filePath string) (*Response, error) {
+	return s.postChangeEdit(ctx, changeID, &ChangeEditInput{RestorePath: filePath})
+}
+
+func (s *ChangesService) postChangeEdit(ctx context.Context, changeID string, input *ChangeEditInput) (*Response, error) {
+	u := fmt.Sprintf("changes/%s/edit", changeID)
+
+	req, err := s.client.NewRequest(ctx, "POST", u, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(req, nil)
+}
@@ -0,0 +1,180 @@
+package gerrit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrChangeEditExists is returned by ChangeEditSession.Commit if the change already has a change edit
+// of the calling user, which the session would otherwise publish or delete.
+var ErrChangeEditExists = errors.New("change edit already exists")
+
+// editOperation is a single staged modification of a ChangeEditSession.
+type editOperation struct {
+	description string
+	apply       func(ctx context.Context) error
+}
+
+// ChangeEditSession stages modifications of a change and applies them as a single new patch set.
+//
+// The modifications are only sent to Gerrit on Commit: they are applied to a change edit,
+// which is published if all of them succeed and deleted otherwise.
+// A ChangeEditSession must not be used concurrently.
+type ChangeEditSession struct {
+	s        *ChangesService
+	changeID string
+	ops      []editOperation
+}
+
+// NewChangeEditSession starts a new ChangeEditSession for the change.
+func (s *ChangesService) NewChangeEditSession(changeID string) *ChangeEditSession {
+	return &ChangeEditSession{s: s, changeID: changeID}
+}
+
+func (e *ChangeEditSession) stage(description string, apply func(ctx context.Context) (*Response, error)) {
+	e.ops = append(e.ops, editOperation{
+		description: description,
+		apply: func(ctx context.Context) error {
+			_, err := apply(ctx)
+			return err
+		},
+	})
+}
+
+// WriteFile stages setting the content of a file. The file is created if it doesn't exist.
+func (e *ChangeEditSession) WriteFile(filePath, content string) {
+	e.stage("writing "+filePath, func(ctx context.Context) (*Response, error) {
+		return e.s.ChangeFileContentInChangeEdit(ctx, e.changeID, filePath, content)
+	})
+}
+
+// DeleteFile stages deleting a file.
+func (e *ChangeEditSession) DeleteFile(filePath string) {
+	e.stage("deleting "+filePath, func(ctx context.Context) (*Response, error) {
+		return e.s.DeleteFileInChangeEdit(ctx, e.changeID, filePath)
+	})
+}
+
+// RenameFile stages renaming a file.
+func (e *ChangeEditSession) RenameFile(oldPath, newPath string) {
+	e.stage("renaming "+oldPath+" to "+newPath, func(ctx context.Context) (*Response, error) {
+		return e.s.RenameFileInChangeEdit(ctx, e.changeID, oldPath, newPath)
+	})
+}
+
+// RestoreFile stages restoring a file to its content in the current patch set.
+func (e *ChangeEditSession) RestoreFile(filePath string) {
+	e.stage("restoring "+filePath, func(ctx context.Context) (*Response, error) {
+		return e.s.RestoreFileInChangeEdit(ctx, e.changeID, filePath)
+	})
+}
+
+// SetMessage stages changing the commit message.
+func (e *ChangeEditSession) SetMessage(message string) {
+	e.stage("changing the commit message", func(ctx context.Context) (*Response, error) {
+		return e.s.ChangeCommitMessageInChangeEdit(ctx, e.changeID, &ChangeEditMessageInput{Message: message})
+	})
+}
+
+// Len returns the number of staged modifications.
+func (e *ChangeEditSession) Len() int {
+	return len(e.ops)
+}
+
+// Commit applies the staged modifications to a new change edit and publishes it as a new patch set.
+// notify is passed on to PublishChangeEdit.
+//
+// If a new patch set was uploaded in the meantime, the change edit is rebased before it is published.
+// If any step fails, the change edit is deleted again and the error of the failed step is returned.
+// Commit refuses to start with ErrChangeEditExists if the change already has a change edit.
+//
+// The staged modifications are kept, so Commit may be retried.
+func (e *ChangeEditSession) Commit(ctx context.Context, notify string) error {
+	if len(e.ops) == 0 {
+		return nil
+	}
+	edit, err := e.s.getChangeEdit(ctx, e.changeID)
+	if err != nil {
+		return err
+	}
+	if edit != nil {
+		return ErrChangeEditExists
+	}
+
+	if err := e.commit(ctx, notify); err != nil {
+		if _, rollbackErr := e.s.DeleteChangeEdit(ctx, e.changeID); rollbackErr != nil {
+			return fmt.Errorf("%v (deleting the change edit failed as well: %v)", err, rollbackErr)
+		}
+		return err
+	}
+	return nil
+}
+
+func (e *ChangeEditSession) commit(ctx context.Context, notify string) error {
+	for _, op := range e.ops {
+		if err := op.apply(ctx); err != nil {
+			return fmt.Errorf("%s: %v", op.description, err)
+		}
+	}
+
+	edit, err := e.s.getChangeEdit(ctx, e.changeID)
+	if err != nil {
+		return fmt.Errorf("retrieving the change edit: %v", err)
+	}
+	change, _, err := e.s.GetChange(ctx, e.changeID, &ChangeOptions{AdditionalFields: []string{"CURRENT_REVISION"}})
+	if err != nil {
+		return fmt.Errorf("retrieving the change: %v", err)
+	}
+	if edit != nil && editIsStale(edit, change) {
+		if _, err := e.s.RebaseChangeEdit(ctx, e.changeID); err != nil {
+			return fmt.Errorf("rebasing the change edit: %v", err)
+		}
+	}
+
+	if _, err := e.s.PublishChangeEdit(ctx, e.changeID, notify); err != nil {
+		return fmt.Errorf("publishing the change edit: %v", err)
+	}
+	return nil
+}
+
+// editIsStale reports whether the change edit is based on an older patch set than the current one of change,
+// which must have been retrieved with the CURRENT_REVISION option.
+func editIsStale(edit *EditInfo, change *ChangeInfo) bool {
+	current := change.CurrentRevisionNumber
+	if current == 0 {
+		current = change.Revisions[change.CurrentRevision].Number
+	}
+	if edit.BasePatchSetNumber != 0 && current != 0 {
+		return edit.BasePatchSetNumber != current
+	}
+	return edit.BaseRevision != "" && edit.BaseRevision != change.CurrentRevision
+}
+
+// getChangeEdit is like GetChangeEditDetails, but returns nil if the change has no change edit.
+func (s *ChangesService) getChangeEdit(ctx context.Context, changeID string) (*EditInfo, error) {
+	u := fmt.Sprintf("changes/%s/edit", changeID)
+
+	req, err := s.client.NewRequest(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+	resp, err := s.client.Do(req, &body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNoContent || body.Len() == 0 {
+		return nil, nil
+	}
+
+	v := new(EditInfo)
+	if err := json.Unmarshal(RemoveMagicPrefixLine(body.Bytes()), v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
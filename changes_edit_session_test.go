@@ -0,0 +1,139 @@
+package gerrit_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/andygrunwald/go-gerrit"
+)
+
+// editSessionServer records the change edit requests for change 123 and simulates the change edit.
+type editSessionServer struct {
+	calls   []string
+	editing bool
+	failOn  string
+	// basePatchSet is the patch set the change edit is based on, the current patch set is 2.
+	basePatchSet int
+}
+
+func (e *editSessionServer) register(t *testing.T) {
+	testMux.HandleFunc("/changes/123/edit", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			if !e.editing {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			base := e.basePatchSet
+			if base == 0 {
+				base = 1
+			}
+			fmt.Fprintf(w, `{"commit": {"commit": "e1"}, "base_patch_set_number": %d, "base_revision": "rev%d"}`, base, base)
+			return
+		case "POST":
+			var input gerrit.ChangeEditInput
+			if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+				t.Error(err)
+			}
+			e.record(w, fmt.Sprintf("POST %s>%s|%s", input.OldPath, input.NewPath, input.RestorePath))
+		case "DELETE":
+			e.editing = false
+			e.calls = append(e.calls, "DELETE edit")
+		}
+	})
+	testMux.HandleFunc("/changes/123/edit/", func(w http.ResponseWriter, r *http.Request) {
+		e.record(w, r.Method+" "+r.URL.Path[len("/changes/123/edit/"):])
+	})
+	testMux.HandleFunc("/changes/123", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"_number": 123, "current_revision": "rev2", "revisions": {"rev2": {"_number": 2}}}`)
+	})
+	testMux.HandleFunc("/changes/123/edit:rebase", func(w http.ResponseWriter, r *http.Request) {
+		e.record(w, "rebase")
+	})
+	testMux.HandleFunc("/changes/123/edit:publish", func(w http.ResponseWriter, r *http.Request) {
+		e.record(w, "publish")
+		e.editing = false
+	})
+}
+
+func (e *editSessionServer) record(w http.ResponseWriter, call string) {
+	e.calls = append(e.calls, call)
+	if call == e.failOn {
+		http.Error(w, "conflict", http.StatusConflict)
+		return
+	}
+	e.editing = true
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func newTestEditSession() *gerrit.ChangeEditSession {
+	session := testClient.Changes.NewChangeEditSession("123")
+	session.WriteFile("a.go", "package a")
+	session.RenameFile("b.go", "c.go")
+	session.RestoreFile("d.go")
+	session.DeleteFile("e.go")
+	return session
+}
+
+func TestChangeEditSession_Commit(t *testing.T) {
+	setup()
+	defer teardown()
+	server := &editSessionServer{}
+	server.register(t)
+
+	if err := newTestEditSession().Commit(context.Background(), "NONE"); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"PUT a.go", "POST b.go>c.go|", "POST >|d.go", "DELETE e.go", "rebase", "publish"}
+	if !reflect.DeepEqual(server.calls, want) {
+		t.Errorf("calls:\ngot:  %v\nwant: %v", server.calls, want)
+	}
+}
+
+func TestChangeEditSession_CommitUpToDate(t *testing.T) {
+	setup()
+	defer teardown()
+	server := &editSessionServer{basePatchSet: 2}
+	server.register(t)
+
+	if err := newTestEditSession().Commit(context.Background(), "NONE"); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"PUT a.go", "POST b.go>c.go|", "POST >|d.go", "DELETE e.go", "publish"}
+	if !reflect.DeepEqual(server.calls, want) {
+		t.Errorf("calls:\ngot:  %v\nwant: %v", server.calls, want)
+	}
+}
+
+func TestChangeEditSession_CommitRollback(t *testing.T) {
+	setup()
+	defer teardown()
+	server := &editSessionServer{failOn: "POST >|d.go"}
+	server.register(t)
+
+	if err := newTestEditSession().Commit(context.Background(), ""); err == nil {
+		t.Fatal("Expected error")
+	}
+	want := []string{"PUT a.go", "POST b.go>c.go|", "POST >|d.go", "DELETE edit"}
+	if !reflect.DeepEqual(server.calls, want) {
+		t.Errorf("calls:\ngot:  %v\nwant: %v", server.calls, want)
+	}
+}
+
+func TestChangeEditSession_CommitExistingEdit(t *testing.T) {
+	setup()
+	defer teardown()
+	server := &editSessionServer{editing: true}
+	server.register(t)
+
+	if err := newTestEditSession().Commit(context.Background(), ""); err != gerrit.ErrChangeEditExists {
+		t.Errorf("Commit() = %v, want %v", err, gerrit.ErrChangeEditExists)
+	}
+	if len(server.calls) != 0 {
+		t.Errorf("Unexpected calls %v", server.calls)
+	}
+}
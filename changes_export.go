@@ -0,0 +1,403 @@
+package gerrit
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportFormat is the output format of a ChangeWriter.
+type ExportFormat string
+
+const (
+	// ExportCSV writes a header row with the column names followed by one row per change.
+	ExportCSV ExportFormat = "csv"
+	// ExportJSONLines writes one JSON object per line and change, with the column names as keys.
+	ExportJSONLines ExportFormat = "jsonl"
+)
+
+// DefaultExportColumns are the columns exported if no columns are selected.
+var DefaultExportColumns = []string{"number", "project", "branch", "subject", "status", "owner", "created", "updated"}
+
+// exportColumn describes how a column of a change export is computed.
+type exportColumn struct {
+	// options are the additional fields the change must be queried with.
+	options []string
+	value   func(c *ChangeInfo) interface{}
+}
+
+// exportColumns are the columns that can be selected by name.
+var exportColumns = map[string]exportColumn{
+	"id":                       {value: func(c *ChangeInfo) interface{} { return c.ID }},
+	"number":                   {value: func(c *ChangeInfo) interface{} { return c.Number }},
+	"change_id":                {value: func(c *ChangeInfo) interface{} { return c.ChangeID }},
+	"url":                      {value: func(c *ChangeInfo) interface{} { return c.URL }},
+	"project":                  {value: func(c *ChangeInfo) interface{} { return c.Project }},
+	"branch":                   {value: func(c *ChangeInfo) interface{} { return c.Branch }},
+	"topic":                    {value: func(c *ChangeInfo) interface{} { return c.Topic }},
+	"subject":                  {value: func(c *ChangeInfo) interface{} { return c.Subject }},
+	"status":                   {value: func(c *ChangeInfo) interface{} { return c.Status }},
+	"created":                  {value: func(c *ChangeInfo) interface{} { return c.Created.Time }},
+	"updated":                  {value: func(c *ChangeInfo) interface{} { return c.Updated.Time }},
+	"submitted":                {value: func(c *ChangeInfo) interface{} { return timestampValue(c.Submitted) }},
+	"owner":                    {options: []string{"DETAILED_ACCOUNTS"}, value: func(c *ChangeInfo) interface{} { return accountValue(c.Owner) }},
+	"submitter":                {options: []string{"DETAILED_ACCOUNTS"}, value: func(c *ChangeInfo) interface{} { return accountValue(c.Submitter) }},
+	"insertions":               {value: func(c *ChangeInfo) interface{} { return c.Insertions }},
+	"deletions":                {value: func(c *ChangeInfo) interface{} { return c.Deletions }},
+	"hashtags":                 {value: func(c *ChangeInfo) interface{} { return c.Hashtags }},
+	"work_in_progress":         {value: func(c *ChangeInfo) interface{} { return c.WorkInProgress }},
+	"is_private":               {value: func(c *ChangeInfo) interface{} { return c.IsPrivate }},
+	"total_comment_count":      {value: func(c *ChangeInfo) interface{} { return c.TotalCommentCount }},
+	"unresolved_comment_count": {value: func(c *ChangeInfo) interface{} { return c.UnresolvedCommentCount }},
+	"current_revision":         {options: []string{"CURRENT_REVISION"}, value: func(c *ChangeInfo) interface{} { return c.CurrentRevision }},
+	"reviewers": {options: []string{"DETAILED_LABELS", "DETAILED_ACCOUNTS"}, value: func(c *ChangeInfo) interface{} {
+		reviewers := make([]string, 0, len(c.Reviewers["REVIEWER"]))
+		for _, a := range c.Reviewers["REVIEWER"] {
+			if v, ok := accountValue(a).(string); ok {
+				reviewers = append(reviewers, v)
+			}
+		}
+		return reviewers
+	}},
+	"messages": {options: []string{"MESSAGES"}, value: func(c *ChangeInfo) interface{} { return len(c.Messages) }},
+	"human_messages": {options: []string{"MESSAGES"}, value: func(c *ChangeInfo) interface{} {
+		n := 0
+		for i := range c.Messages {
+			if !c.Messages[i].IsAutogenerated() {
+				n++
+			}
+		}
+		return n
+	}},
+	"time_to_first_review": {options: []string{"MESSAGES"}, value: func(c *ChangeInfo) interface{} {
+		for i := range c.Messages {
+			m := &c.Messages[i]
+			if m.Author.AccountID != c.Owner.AccountID && m.Author.AccountID != 0 && !m.IsAutogenerated() {
+				return int64(m.Date.Sub(c.Created.Time) / time.Second)
+			}
+		}
+		return nil
+	}},
+	"time_to_submit": {value: func(c *ChangeInfo) interface{} {
+		if c.Submitted == nil {
+			return nil
+		}
+		return int64(c.Submitted.Sub(c.Created.Time) / time.Second)
+	}},
+}
+
+const (
+	exportLabelPrefix            = "label."
+	exportCustomKeyedValuePrefix = "custom_keyed_value."
+)
+
+// ExportColumns returns the names of the columns that can be selected for a change export, sorted.
+// Besides these, the prefixed columns "label.<name>" and "custom_keyed_value.<key>" can be selected.
+func ExportColumns() []string {
+	names := make([]string, 0, len(exportColumns))
+	for name := range exportColumns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookupExportColumn returns the column with the given name.
+func lookupExportColumn(name string) (exportColumn, error) {
+	if c, ok := exportColumns[name]; ok {
+		return c, nil
+	}
+	if label := strings.TrimPrefix(name, exportLabelPrefix); label != name && label != "" {
+		return exportColumn{options: []string{"DETAILED_LABELS"}, value: func(c *ChangeInfo) interface{} {
+			l, ok := c.Labels[label]
+			if !ok {
+				return nil
+			}
+			s, err := l.Summarize(label)
+			if err != nil {
+				return nil
+			}
+			if s.MinVote < 0 {
+				return s.MinVote
+			}
+			return s.MaxVote
+		}}, nil
+	}
+	if key := strings.TrimPrefix(name, exportCustomKeyedValuePrefix); key != name && key != "" {
+		return exportColumn{value: func(c *ChangeInfo) interface{} {
+			v, ok := c.CustomKeyedValues[key]
+			if !ok {
+				return nil
+			}
+			return v
+		}}, nil
+	}
+	return exportColumn{}, fmt.Errorf("unknown export column %q", name)
+}
+
+// timestampValue returns the time of t, or nil if t is nil.
+func timestampValue(t *Timestamp) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.Time
+}
+
+// accountValue flattens an account to its email address.
+// Accounts without email are represented by their username, their name
+// or their account ID, in this order of preference.
+func accountValue(a AccountInfo) interface{} {
+	switch {
+	case a.Email != "":
+		return a.Email
+	case a.Username != "":
+		return a.Username
+	case a.Name != "":
+		return a.Name
+	case a.AccountID != 0:
+		return strconv.Itoa(a.AccountID)
+	default:
+		return nil
+	}
+}
+
+// ChangeWriter writes changes as CSV or JSON Lines, one change per row or line.
+//
+// Nested fields are flattened as follows, so that the output can be imported into
+// spreadsheets and databases:
+//
+//   - Timestamps are written in RFC 3339 format in UTC, e.g. "2015-06-02T13:36:07Z".
+//   - Accounts are written as their email address, or if it is unknown as their username,
+//     name or account ID.
+//   - Lists, like hashtags, are written as JSON arrays in JSON Lines and joined with ";" in CSV.
+//   - Missing values, like the submission time of an open change, are written as null in
+//     JSON Lines and as empty string in CSV.
+//   - Durations, like time_to_first_review, are written as number of seconds.
+//   - "label.<name>" is the most negative vote on the label if there is one,
+//     otherwise the most positive vote, or 0 if nobody voted.
+//     It is missing if the label does not apply to the change.
+//   - time_to_first_review is measured from the creation of the change to the first
+//     message of someone other than the owner that is not autogenerated.
+type ChangeWriter struct {
+	format  ExportFormat
+	names   []string
+	columns []exportColumn
+
+	w             io.Writer
+	csv           *csv.Writer
+	headerWritten bool
+}
+
+// NewChangeWriter returns a ChangeWriter that writes the given columns of changes to w.
+// If columns is empty, DefaultExportColumns are written.
+// See ExportColumns for the columns that can be selected.
+func NewChangeWriter(w io.Writer, format ExportFormat, columns []string) (*ChangeWriter, error) {
+	if format != ExportCSV && format != ExportJSONLines {
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+	if len(columns) == 0 {
+		columns = DefaultExportColumns
+	}
+
+	cw := &ChangeWriter{format: format, names: columns, w: w}
+	for _, name := range columns {
+		c, err := lookupExportColumn(name)
+		if err != nil {
+			return nil, err
+		}
+		cw.columns = append(cw.columns, c)
+	}
+	if format == ExportCSV {
+		cw.csv = csv.NewWriter(w)
+	}
+	return cw, nil
+}
+
+// AdditionalFields returns the additional fields changes must be queried with,
+// so that all columns of the ChangeWriter can be computed.
+func (cw *ChangeWriter) AdditionalFields() []string {
+	var fields []string
+	for _, c := range cw.columns {
+		fields = mergeFields(fields, c.options)
+	}
+	return fields
+}
+
+// Write writes a change.
+func (cw *ChangeWriter) Write(change *ChangeInfo) error {
+	if cw.format == ExportJSONLines {
+		return cw.writeJSONLine(change)
+	}
+
+	if err := cw.writeHeader(); err != nil {
+		return err
+	}
+	record := make([]string, len(cw.columns))
+	for i, c := range cw.columns {
+		record[i] = csvValue(c.value(change))
+	}
+	return cw.csv.Write(record)
+}
+
+// Flush writes any buffered data to the underlying io.Writer.
+// For CSV, the header row is written even if no change was written.
+func (cw *ChangeWriter) Flush() error {
+	if cw.csv == nil {
+		return nil
+	}
+	if err := cw.writeHeader(); err != nil {
+		return err
+	}
+	cw.csv.Flush()
+	return cw.csv.Error()
+}
+
+func (cw *ChangeWriter) writeHeader() error {
+	if cw.headerWritten {
+		return nil
+	}
+	cw.headerWritten = true
+	return cw.csv.Write(cw.names)
+}
+
+// writeJSONLine writes a change as JSON object, keeping the order of the columns.
+func (cw *ChangeWriter) writeJSONLine(change *ChangeInfo) error {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, c := range cw.columns {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(cw.names[i])
+		if err != nil {
+			return err
+		}
+		v := c.value(change)
+		if t, ok := v.(time.Time); ok {
+			v = t.UTC().Format(time.RFC3339Nano)
+		}
+		value, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteString("}\n")
+	_, err := cw.w.Write(buf.Bytes())
+	return err
+}
+
+// csvValue formats a column value for CSV.
+func csvValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	case []string:
+		return strings.Join(v, ";")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// ExportChangesOptions specifies the parameters for ExportChanges.
+type ExportChangesOptions struct {
+	// Query is the change query, e.g. "status:merged after:2024-01-01".
+	Query string
+
+	// Format is the output format. It defaults to ExportCSV.
+	Format ExportFormat
+
+	// Columns are the columns to export, see ExportColumns.
+	// It defaults to DefaultExportColumns.
+	Columns []string
+
+	// PageSize is the number of changes requested at once. It defaults to 100.
+	PageSize int
+
+	// Limit is the maximum number of changes to export. If it is 0, all matching changes are exported.
+	Limit int
+
+	// AdditionalFields are requested in addition to the fields needed by the columns.
+	AdditionalFields []string
+}
+
+// ExportChanges queries changes page by page and writes them to w.
+// It returns the number of exported changes.
+//
+// The additional fields needed by the columns, e.g. MESSAGES for time_to_first_review,
+// are requested automatically.
+func (s *ChangesService) ExportChanges(ctx context.Context, w io.Writer, opt *ExportChangesOptions) (int, error) {
+	if opt == nil {
+		opt = &ExportChangesOptions{}
+	}
+	format := opt.Format
+	if format == "" {
+		format = ExportCSV
+	}
+	cw, err := NewChangeWriter(w, format, opt.Columns)
+	if err != nil {
+		return 0, err
+	}
+	pageSize := opt.PageSize
+	if pageSize <= 0 {
+		pageSize = 100
+	}
+
+	query := &QueryChangeOptions{
+		ChangeOptions: ChangeOptions{AdditionalFields: mergeFields(cw.AdditionalFields(), opt.AdditionalFields)},
+	}
+	if opt.Query != "" {
+		query.Query = []string{opt.Query}
+	}
+
+	n := 0
+	for opt.Limit == 0 || n < opt.Limit {
+		query.Limit = pageSize
+		if opt.Limit > 0 && opt.Limit-n < pageSize {
+			query.Limit = opt.Limit - n
+		}
+		query.Start = n
+
+		changes, _, err := s.QueryChanges(ctx, query)
+		if err != nil {
+			return n, err
+		}
+		for i := range *changes {
+			if err := cw.Write(&(*changes)[i]); err != nil {
+				return n, err
+			}
+			n++
+		}
+		if len(*changes) == 0 || !(*changes)[len(*changes)-1].MoreChanges {
+			break
+		}
+	}
+	return n, cw.Flush()
+}
+
+// mergeFields returns the union of the additional fields a and b, sorted.
+func mergeFields(a, b []string) []string {
+	seen := map[string]bool{}
+	var fields []string
+	for _, f := range append(append([]string{}, a...), b...) {
+		if !seen[f] {
+			seen[f] = true
+			fields = append(fields, f)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
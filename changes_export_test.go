@@ -0,0 +1,134 @@
+package gerrit_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/andygrunwald/go-gerrit"
+)
+
+func TestChangesService_ExportChanges(t *testing.T) {
+	setup()
+	defer teardown()
+
+	pages := map[string]string{
+		"": `)]}'
+[
+  {"_number": 3, "project": "tools", "branch": "master", "subject": "Fix \"quoted\" build", "status": "MERGED",
+   "owner": {"_account_id": 1000, "email": "jdoe@example.com"},
+   "created": "2024-01-02 10:00:00.000000000", "updated": "2024-01-03 10:00:00.000000000"},
+  {"_number": 2, "project": "tools", "branch": "master", "subject": "Add flag", "status": "NEW",
+   "owner": {"_account_id": 1001, "username": "jroe"},
+   "created": "2024-01-01 10:00:00.000000000", "updated": "2024-01-02 09:00:00.000000000", "_more_changes": true}
+]`,
+		"2": `)]}'
+[
+  {"_number": 1, "project": "docs", "branch": "main", "subject": "Initial", "status": "ABANDONED",
+   "owner": {"_account_id": 1000, "email": "jdoe@example.com"},
+   "created": "2023-12-24 10:00:00.000000000", "updated": "2023-12-25 10:00:00.000000000"}
+]`,
+	}
+	testMux.HandleFunc("/changes/", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		q := r.URL.Query()
+		if got, want := q["q"], []string{"after:2023-12-01"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("q = %v, want %v", got, want)
+		}
+		if got, want := q.Get("n"), "2"; got != want {
+			t.Errorf("n = %q, want %q", got, want)
+		}
+		if got, want := q["o"], []string{"DETAILED_ACCOUNTS"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("o = %v, want %v", got, want)
+		}
+		fmt.Fprint(w, pages[q.Get("start")]) // nolint: errcheck
+	})
+
+	var buf bytes.Buffer
+	n, err := testClient.Changes.ExportChanges(context.Background(), &buf, &gerrit.ExportChangesOptions{
+		Query:    "after:2023-12-01",
+		PageSize: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Errorf("ExportChanges() = %d, want 3", n)
+	}
+
+	want := `number,project,branch,subject,status,owner,created,updated
+3,tools,master,"Fix ""quoted"" build",MERGED,jdoe@example.com,2024-01-02T10:00:00Z,2024-01-03T10:00:00Z
+2,tools,master,Add flag,NEW,jroe,2024-01-01T10:00:00Z,2024-01-02T09:00:00Z
+1,docs,main,Initial,ABANDONED,jdoe@example.com,2023-12-24T10:00:00Z,2023-12-25T10:00:00Z
+`
+	if buf.String() != want {
+		t.Errorf("ExportChanges() wrote:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestChangeWriter_JSONLines(t *testing.T) {
+	created := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	owner := gerrit.AccountInfo{AccountID: 1000, Email: "jdoe@example.com"}
+	reviewer := gerrit.AccountInfo{AccountID: 1001, Username: "jroe"}
+	changes := []gerrit.ChangeInfo{
+		{
+			Number:   3,
+			Owner:    owner,
+			Created:  gerrit.Timestamp{Time: created},
+			Hashtags: []string{"ci", "flaky"},
+			Labels: map[string]gerrit.LabelInfo{
+				"Code-Review": {
+					Values: map[string]string{"-2": "", "-1": "", " 0": "", "+1": "", "+2": ""},
+					All: []gerrit.ApprovalInfo{
+						{AccountInfo: reviewer, Value: 2},
+						{AccountInfo: gerrit.AccountInfo{AccountID: 1002}, Value: -1},
+					},
+				},
+			},
+			Messages: []gerrit.ChangeMessageInfo{
+				{Author: owner, Date: gerrit.Timestamp{Time: created}, Message: "Uploaded patch set 1.", Tag: "autogenerated:gerrit:newPatchSet"},
+				{Author: gerrit.AccountInfo{AccountID: 1003}, Date: gerrit.Timestamp{Time: created.Add(time.Minute)}, Message: "Build started", Tag: "autogenerated:ci"},
+				{Author: reviewer, Date: gerrit.Timestamp{Time: created.Add(90 * time.Minute)}, Message: "Patch Set 1: Code-Review+2"},
+			},
+			CustomKeyedValues: map[string]string{"build-id": "4711"},
+		},
+		{Number: 2, Owner: owner, Created: gerrit.Timestamp{Time: created}},
+	}
+
+	var buf bytes.Buffer
+	cw, err := gerrit.NewChangeWriter(&buf, gerrit.ExportJSONLines, []string{
+		"number", "owner", "hashtags", "label.Code-Review", "messages", "human_messages",
+		"time_to_first_review", "submitted", "custom_keyed_value.build-id",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := cw.AdditionalFields(), []string{"DETAILED_ACCOUNTS", "DETAILED_LABELS", "MESSAGES"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AdditionalFields() = %v, want %v", got, want)
+	}
+	for i := range changes {
+		if err := cw.Write(&changes[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := cw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"number":3,"owner":"jdoe@example.com","hashtags":["ci","flaky"],"label.Code-Review":-1,"messages":3,"human_messages":1,"time_to_first_review":5400,"submitted":null,"custom_keyed_value.build-id":"4711"}
+{"number":2,"owner":"jdoe@example.com","hashtags":null,"label.Code-Review":null,"messages":0,"human_messages":0,"time_to_first_review":null,"submitted":null,"custom_keyed_value.build-id":null}
+`
+	if buf.String() != want {
+		t.Errorf("ChangeWriter wrote:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestNewChangeWriter_UnknownColumn(t *testing.T) {
+	if _, err := gerrit.NewChangeWriter(&bytes.Buffer{}, gerrit.ExportCSV, []string{"number", "label."}); err == nil {
+		t.Error("NewChangeWriter() with unknown column succeeded")
+	}
+}
@@ -0,0 +1,193 @@
+package gerrit
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// changeIDPattern matches the Change-Id footer value written by the commit-msg hook.
+var changeIDPattern = regexp.MustCompile(`^I[0-9a-f]{40}$`)
+
+// ChangeID identifies a change.
+// Gerrit accepts several identifiers for a change in its REST API:
+// the legacy numeric change number, the Change-Id footer ("I<sha1>"),
+// "project~branch~Change-Id" triplets and "project~number".
+//
+// Use ParseChangeID or ParseChangeURL to construct a ChangeID and String
+// to render the escaped identifier that can be passed to the ChangesService methods.
+//
+// Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#change-id
+type ChangeID struct {
+	// Project is the name of the project, e.g. "plugins/replication".
+	Project string
+	// Branch is the destination branch of the change.
+	Branch string
+	// ChangeID is the Change-Id footer value, e.g. "I8473b95934b5732ac55d26311a706c9c2bde9940".
+	ChangeID string
+	// Number is the legacy numeric ID of the change.
+	Number int
+
+	// PatchSet is the patch set number if the identifier was parsed from a web URL pointing to a patch set.
+	PatchSet int
+	// BasePatchSet is the patch set the web URL diffs against ("1..3"), if any.
+	BasePatchSet int
+	// File is the path of the file if the identifier was parsed from a web URL pointing to a file.
+	File string
+	// Edit reports whether the web URL points to the change edit ("/12345/edit/file").
+	Edit bool
+	// CommentID is the UUID of the comment if the web URL links to a comment ("/12345/comment/<uuid>/").
+	CommentID string
+}
+
+// ParseChangeID parses a change identifier as accepted by the Gerrit REST API.
+// Supported forms are "12345", "I8473b95934b5732ac55d26311a706c9c2bde9940",
+// "project~12345" and "project~branch~I8473b95934b5732ac55d26311a706c9c2bde9940".
+// The project and branch parts may be URL-escaped.
+func ParseChangeID(s string) (*ChangeID, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, fmt.Errorf("invalid change identifier %q", s)
+	}
+
+	parts := strings.Split(s, "~")
+	switch len(parts) {
+	case 1:
+		if n, err := strconv.Atoi(s); err == nil && n > 0 {
+			return &ChangeID{Number: n}, nil
+		}
+		if changeIDPattern.MatchString(s) {
+			return &ChangeID{ChangeID: s}, nil
+		}
+	case 2:
+		project, err := url.PathUnescape(parts[0])
+		if err != nil || project == "" {
+			break
+		}
+		if n, err := strconv.Atoi(parts[1]); err == nil && n > 0 {
+			return &ChangeID{Project: project, Number: n}, nil
+		}
+	case 3:
+		project, err := url.PathUnescape(parts[0])
+		if err != nil || project == "" {
+			break
+		}
+		branch, err := url.PathUnescape(parts[1])
+		if err != nil || branch == "" {
+			break
+		}
+		if changeIDPattern.MatchString(parts[2]) {
+			return &ChangeID{Project: project, Branch: branch, ChangeID: parts[2]}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("invalid change identifier %q", s)
+}
+
+// ParseChangeURL parses the web URL of a change, for example
+// "https://gerrit-review.googlesource.com/c/gerrit/+/12345/3/java/Main.java".
+//
+// Besides the change itself, the patch set (and the base patch set of ranges like "1..3")
+// and the file are extracted if present, as well as the file of change edit URLs
+// ("/12345/edit/java/Main.java") and the comment of comment links ("/12345/comment/<uuid>/").
+// URLs of the old UI ("https://host/#/c/12345/3") and short links ("https://host/12345")
+// are supported as well.
+//
+// The project is taken from the first "/c/" of the path, so a base path of the Gerrit server
+// must not contain "/c/".
+func ParseChangeURL(rawURL string) (*ChangeID, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return nil, err
+	}
+
+	p := u.Path
+	if strings.HasPrefix(u.Fragment, "/c/") {
+		p = u.Fragment
+	}
+
+	id := &ChangeID{}
+	var rest string
+	if i := strings.Index(p, "/+/"); i >= 0 {
+		project := p[:i]
+		if j := strings.Index(project, "/c/"); j >= 0 {
+			project = project[j+len("/c/"):]
+		} else {
+			return nil, fmt.Errorf("invalid change URL %q", rawURL)
+		}
+		id.Project = project
+		rest = p[i+len("/+/"):]
+	} else if i := strings.Index(p, "/c/"); i >= 0 {
+		rest = p[i+len("/c/"):]
+	} else {
+		rest = strings.TrimPrefix(p, "/")
+	}
+
+	segments := strings.Split(strings.TrimSuffix(rest, "/"), "/")
+	id.Number, err = strconv.Atoi(segments[0])
+	if err != nil || id.Number <= 0 {
+		return nil, fmt.Errorf("invalid change URL %q", rawURL)
+	}
+
+	switch {
+	case len(segments) > 1 && segments[1] == "edit":
+		id.Edit = true
+		if len(segments) > 2 {
+			id.File = strings.Join(segments[2:], "/")
+		}
+		return id, nil
+	case len(segments) > 1 && segments[1] == "comment":
+		if len(segments) != 3 || segments[2] == "" {
+			return nil, fmt.Errorf("invalid comment link %q", rawURL)
+		}
+		id.CommentID = segments[2]
+		return id, nil
+	}
+
+	if len(segments) > 1 && segments[1] != "" {
+		ps := segments[1]
+		if i := strings.Index(ps, ".."); i >= 0 {
+			if ps[:i] != "" && ps[:i] != "edit" {
+				if id.BasePatchSet, err = strconv.Atoi(ps[:i]); err != nil {
+					return nil, fmt.Errorf("invalid patch set %q in change URL %q", ps, rawURL)
+				}
+			}
+			ps = ps[i+len(".."):]
+		}
+		if id.PatchSet, err = strconv.Atoi(ps); err != nil {
+			return nil, fmt.Errorf("invalid patch set %q in change URL %q", ps, rawURL)
+		}
+	}
+	if len(segments) > 2 {
+		id.File = strings.Join(segments[2:], "/")
+	}
+
+	return id, nil
+}
+
+// String returns the change identifier in the form expected by the Gerrit REST API.
+// Project and branch are URL-escaped, so the result can be passed to the ChangesService methods as is.
+//
+// The most specific form is chosen: "project~number" if both are known, the numeric ID,
+// "project~branch~Change-Id" and finally the bare Change-Id.
+func (c *ChangeID) String() string {
+	switch {
+	case c.Project != "" && c.Number > 0:
+		return url.PathEscape(c.Project) + "~" + strconv.Itoa(c.Number)
+	case c.Number > 0:
+		return strconv.Itoa(c.Number)
+	case c.Project != "" && c.Branch != "" && c.ChangeID != "":
+		return url.PathEscape(c.Project) + "~" + url.PathEscape(c.Branch) + "~" + c.ChangeID
+	default:
+		return c.ChangeID
+	}
+}
+
+// Ambiguous reports whether the identifier consists of a bare Change-Id only.
+// The same Change-Id can be used for changes on several branches and projects.
+// In this case Gerrit will reject the request if more than one change matches.
+func (c *ChangeID) Ambiguous() bool {
+	return c.Number <= 0 && (c.Project == "" || c.Branch == "")
+}
@@ -0,0 +1,152 @@
+package gerrit_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/andygrunwald/go-gerrit"
+)
+
+func TestParseChangeID(t *testing.T) {
+	tests := []struct {
+		in        string
+		want      *gerrit.ChangeID
+		str       string
+		ambiguous bool
+	}{
+		{
+			in:   "12345",
+			want: &gerrit.ChangeID{Number: 12345},
+			str:  "12345",
+		},
+		{
+			in:        "I8473b95934b5732ac55d26311a706c9c2bde9940",
+			want:      &gerrit.ChangeID{ChangeID: "I8473b95934b5732ac55d26311a706c9c2bde9940"},
+			str:       "I8473b95934b5732ac55d26311a706c9c2bde9940",
+			ambiguous: true,
+		},
+		{
+			in:   "plugins/replication~12345",
+			want: &gerrit.ChangeID{Project: "plugins/replication", Number: 12345},
+			str:  "plugins%2Freplication~12345",
+		},
+		{
+			in:   "plugins%2Freplication~refs/heads/master~I8473b95934b5732ac55d26311a706c9c2bde9940",
+			want: &gerrit.ChangeID{Project: "plugins/replication", Branch: "refs/heads/master", ChangeID: "I8473b95934b5732ac55d26311a706c9c2bde9940"},
+			str:  "plugins%2Freplication~refs%2Fheads%2Fmaster~I8473b95934b5732ac55d26311a706c9c2bde9940",
+		},
+	}
+	for _, tt := range tests {
+		got, err := gerrit.ParseChangeID(tt.in)
+		if err != nil {
+			t.Errorf("ParseChangeID(%q): %v", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseChangeID(%q):\ngot:  %+v\nwant: %+v", tt.in, got, tt.want)
+		}
+		if s := got.String(); s != tt.str {
+			t.Errorf("ParseChangeID(%q).String() = %q, want %q", tt.in, s, tt.str)
+		}
+		if a := got.Ambiguous(); a != tt.ambiguous {
+			t.Errorf("ParseChangeID(%q).Ambiguous() = %t, want %t", tt.in, a, tt.ambiguous)
+		}
+	}
+}
+
+func TestParseChangeID_Invalid(t *testing.T) {
+	for _, in := range []string{"", "abc", "-1", "Iabc", "project~abc", "~123", "p~b~123", "a~b~c~d"} {
+		if _, err := gerrit.ParseChangeID(in); err == nil {
+			t.Errorf("ParseChangeID(%q): expected error", in)
+		}
+	}
+}
+
+func TestParseChangeURL(t *testing.T) {
+	tests := []struct {
+		in   string
+		want *gerrit.ChangeID
+	}{
+		{
+			in:   "https://gerrit-review.googlesource.com/c/gerrit/+/12345",
+			want: &gerrit.ChangeID{Project: "gerrit", Number: 12345},
+		},
+		{
+			in:   "https://gerrit-review.googlesource.com/c/plugins/replication/+/12345/3",
+			want: &gerrit.ChangeID{Project: "plugins/replication", Number: 12345, PatchSet: 3},
+		},
+		{
+			in:   "https://gerrit-review.googlesource.com/c/gerrit/+/12345/1..3/java/com/google/Main.java",
+			want: &gerrit.ChangeID{Project: "gerrit", Number: 12345, BasePatchSet: 1, PatchSet: 3, File: "java/com/google/Main.java"},
+		},
+		{
+			in:   "https://review.example.com/gerrit/c/my%2Fproject/+/42/",
+			want: &gerrit.ChangeID{Project: "my/project", Number: 42},
+		},
+		{
+			in:   "https://review.example.com/#/c/42/2",
+			want: &gerrit.ChangeID{Number: 42, PatchSet: 2},
+		},
+		{
+			in:   "https://review.example.com/42",
+			want: &gerrit.ChangeID{Number: 42},
+		},
+		{
+			in:   "https://review.example.com/c/infra/c/tools/+/123/4",
+			want: &gerrit.ChangeID{Project: "infra/c/tools", Number: 123, PatchSet: 4},
+		},
+		{
+			in:   "https://review.example.com/c/gerrit/+/123/edit/java/Main.java",
+			want: &gerrit.ChangeID{Project: "gerrit", Number: 123, Edit: true, File: "java/Main.java"},
+		},
+		{
+			in:   "https://review.example.com/c/gerrit/+/123/comment/6f5e5bbd_0a6a24f4/",
+			want: &gerrit.ChangeID{Project: "gerrit", Number: 123, CommentID: "6f5e5bbd_0a6a24f4"},
+		},
+	}
+	for _, tt := range tests {
+		got, err := gerrit.ParseChangeURL(tt.in)
+		if err != nil {
+			t.Errorf("ParseChangeURL(%q): %v", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseChangeURL(%q):\ngot:  %+v\nwant: %+v", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"https://review.example.com/", "https://review.example.com/c/gerrit/+/abc", "https://review.example.com/c/gerrit/+/1/x"} {
+		if _, err := gerrit.ParseChangeURL(in); err == nil {
+			t.Errorf("ParseChangeURL(%q): expected error", in)
+		}
+	}
+}
+
+func TestChangeID_String_Request(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.EscapedPath(), "/changes/plugins%2Freplication~12345"; got != want {
+			t.Errorf("request path:\ngot:  %q\nwant: %q", got, want)
+		}
+		_, err := fmt.Fprint(w, `{"_number": 12345, "project": "plugins/replication"}`)
+		if err != nil {
+			t.Error(err)
+		}
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	client := newClient(ctx, t, ts)
+
+	id := &gerrit.ChangeID{Project: "plugins/replication", Number: 12345}
+	change, _, err := client.Changes.GetChange(ctx, id.String(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if change.Number != 12345 {
+		t.Errorf("change.Number = %d, want 12345", change.Number)
+	}
+}
@@ -0,0 +1,185 @@
+package gerrit
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ParseLabelValue parses a label value as used in the keys of LabelInfo.Values
+// and in ChangeInfo.PermittedLabels, e.g. "-2", " 0" or "+1".
+func ParseLabelValue(s string) (int, error) {
+	v, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(s), "+"))
+	if err != nil {
+		return 0, fmt.Errorf("invalid label value %q", s)
+	}
+	return v, nil
+}
+
+// FormatLabelValue formats a label value the way Gerrit does, e.g. "-2", " 0" or "+1".
+func FormatLabelValue(v int) string {
+	switch {
+	case v > 0:
+		return "+" + strconv.Itoa(v)
+	case v == 0:
+		return " 0"
+	default:
+		return strconv.Itoa(v)
+	}
+}
+
+// labelRange returns the lowest and highest of the given label values.
+func labelRange(values []string) (min, max int, err error) {
+	for i, s := range values {
+		v, err := ParseLabelValue(s)
+		if err != nil {
+			return 0, 0, err
+		}
+		if i == 0 || v < min {
+			min = v
+		}
+		if i == 0 || v > max {
+			max = v
+		}
+	}
+	return min, max, nil
+}
+
+// LabelSummary is an evaluation of the votes on a label.
+type LabelSummary struct {
+	// Name of the label, e.g. "Code-Review".
+	Name string
+	// Min and Max are the lowest and highest values the label can take.
+	Min, Max int
+	// MinVote and MaxVote are the lowest and highest votes cast on the label.
+	// They are 0 if there are no votes.
+	MinVote, MaxVote int
+	// Votes are all non-zero votes on the label.
+	Votes []ApprovalInfo
+	// Approvers are the votes with the highest possible value, e.g. Code-Review+2.
+	Approvers []ApprovalInfo
+	// Blockers are the votes with the lowest possible value, e.g. Code-Review-2.
+	Blockers []ApprovalInfo
+	// Approved, Rejected and Blocking are taken from the LabelInfo.
+	Approved bool
+	Rejected bool
+	Blocking bool
+}
+
+// Summarize evaluates the votes on the label.
+// The LabelInfo must have been retrieved with the DETAILED_LABELS option,
+// otherwise only Name, Approved, Rejected and Blocking are set.
+func (l *LabelInfo) Summarize(name string) (*LabelSummary, error) {
+	s := &LabelSummary{
+		Name:     name,
+		Approved: l.Approved.AccountID != 0,
+		Rejected: l.Rejected.AccountID != 0,
+		Blocking: l.Blocking,
+	}
+
+	values := make([]string, 0, len(l.Values))
+	for v := range l.Values {
+		values = append(values, v)
+	}
+	var err error
+	if s.Min, s.Max, err = labelRange(values); err != nil {
+		return nil, err
+	}
+
+	for _, a := range l.All {
+		if a.Value == 0 {
+			continue
+		}
+		if len(s.Votes) == 0 || a.Value < s.MinVote {
+			s.MinVote = a.Value
+		}
+		if len(s.Votes) == 0 || a.Value > s.MaxVote {
+			s.MaxVote = a.Value
+		}
+		s.Votes = append(s.Votes, a)
+		if a.Value > 0 && a.Value == s.Max {
+			s.Approvers = append(s.Approvers, a)
+		}
+		if a.Value < 0 && a.Value == s.Min {
+			s.Blockers = append(s.Blockers, a)
+		}
+	}
+	return s, nil
+}
+
+// SummarizeLabels evaluates the votes on all labels of the change, sorted by label name.
+func SummarizeLabels(change *ChangeInfo) ([]LabelSummary, error) {
+	names := make([]string, 0, len(change.Labels))
+	for name := range change.Labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	summaries := make([]LabelSummary, 0, len(names))
+	for _, name := range names {
+		l := change.Labels[name]
+		s, err := l.Summarize(name)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, *s)
+	}
+	return summaries, nil
+}
+
+// PermittedRange returns the range of values the calling user may vote on label.
+// The ChangeInfo must have been retrieved with the DETAILED_LABELS option.
+// The last return value is false if the user may not vote on the label at all.
+func (c *ChangeInfo) PermittedRange(label string) (min, max int, ok bool) {
+	values := c.PermittedLabels[label]
+	if len(values) == 0 {
+		return 0, 0, false
+	}
+	min, max, err := labelRange(values)
+	if err != nil {
+		return 0, 0, false
+	}
+	return min, max, true
+}
+
+// CanVote reports whether the calling user may vote value on label.
+func (c *ChangeInfo) CanVote(label string, value int) bool {
+	min, max, ok := c.PermittedRange(label)
+	return ok && min <= value && value <= max
+}
+
+// NewReviewInput creates a ReviewInput with the given votes.
+// Each vote is checked against the permitted labels of the calling user,
+// so an error is returned before anything is sent to Gerrit if a vote is out of range.
+// The ChangeInfo must have been retrieved with the DETAILED_LABELS option.
+//
+// Further fields like the message or comments can be set on the returned ReviewInput.
+func NewReviewInput(change *ChangeInfo, labels map[string]int) (*ReviewInput, error) {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := labels[name]
+		min, max, ok := change.PermittedRange(name)
+		if !ok {
+			return nil, fmt.Errorf("voting on label %q is not permitted", name)
+		}
+		if value < min || value > max {
+			return nil, fmt.Errorf("vote %s on label %q is outside of the permitted range [%s, %s]",
+				FormatLabelValue(value), name, FormatLabelValue(min), FormatLabelValue(max))
+		}
+	}
+
+	input := &ReviewInput{}
+	if len(labels) > 0 {
+		input.Labels = make(map[string]int, len(labels))
+		for name, value := range labels {
+			input.Labels[name] = value
+		}
+	}
+	return input, nil
+}
@@ -0,0 +1,116 @@
+package gerrit_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/andygrunwald/go-gerrit"
+)
+
+const detailedLabelsChange = `{
+  "id": "myProject~master~I8473b95934b5732ac55d26311a706c9c2bde9940",
+  "_number": 3965,
+  "labels": {
+    "Verified": {
+      "all": [
+        {"value": 0, "_account_id": 1000096},
+        {"value": 1, "_account_id": 1000098, "date": "2013-02-26 15:40:43.986000000"}
+      ],
+      "values": {"-1": "Fails", " 0": "No score", "+1": "Verified"},
+      "approved": {"_account_id": 1000098}
+    },
+    "Code-Review": {
+      "all": [
+        {"value": -2, "_account_id": 1000096, "date": "2013-02-26 15:41:00.000000000", "permitted_voting_range": {"min": -2, "max": 2}},
+        {"value": 1, "_account_id": 1000097},
+        {"value": 2, "_account_id": 1000098}
+      ],
+      "values": {"-2": "This shall not be merged", "-1": "I would prefer this is not merged as is", " 0": "No score", "+1": "Looks good to me, but someone else must approve", "+2": "Looks good to me, approved"},
+      "rejected": {"_account_id": 1000096},
+      "blocking": true
+    }
+  },
+  "permitted_labels": {
+    "Verified": ["-1", " 0", "+1"],
+    "Code-Review": ["-1", " 0", "+1"]
+  }
+}`
+
+func TestParseLabelValue(t *testing.T) {
+	for s, want := range map[string]int{"-2": -2, " 0": 0, "0": 0, "+1": 1, "+2": 2} {
+		got, err := gerrit.ParseLabelValue(s)
+		if err != nil || got != want {
+			t.Errorf("ParseLabelValue(%q) = %d, %v; want %d", s, got, err, want)
+		}
+		if s != "0" {
+			if f := gerrit.FormatLabelValue(want); f != s {
+				t.Errorf("FormatLabelValue(%d) = %q, want %q", want, f, s)
+			}
+		}
+	}
+	if _, err := gerrit.ParseLabelValue("x"); err == nil {
+		t.Error("ParseLabelValue(\"x\"): expected error")
+	}
+}
+
+func TestSummarizeLabels(t *testing.T) {
+	var change gerrit.ChangeInfo
+	if err := json.Unmarshal([]byte(detailedLabelsChange), &change); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := change.Labels["Code-Review"].All[0].Date.Time, time.Date(2013, 2, 26, 15, 41, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("ApprovalInfo.Date = %v, want %v", got, want)
+	}
+
+	summaries, err := gerrit.SummarizeLabels(&change)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(summaries) != 2 || summaries[0].Name != "Code-Review" || summaries[1].Name != "Verified" {
+		t.Fatalf("Unexpected summaries %+v", summaries)
+	}
+
+	cr := summaries[0]
+	if cr.Min != -2 || cr.Max != 2 || cr.MinVote != -2 || cr.MaxVote != 2 {
+		t.Errorf("Code-Review range: %+v", cr)
+	}
+	if len(cr.Votes) != 3 || len(cr.Approvers) != 1 || cr.Approvers[0].AccountID != 1000098 {
+		t.Errorf("Code-Review approvers: %+v", cr.Approvers)
+	}
+	if len(cr.Blockers) != 1 || cr.Blockers[0].AccountID != 1000096 || !cr.Rejected || !cr.Blocking {
+		t.Errorf("Code-Review blockers: %+v", cr.Blockers)
+	}
+
+	v := summaries[1]
+	if len(v.Votes) != 1 || !v.Approved || len(v.Approvers) != 1 || len(v.Blockers) != 0 {
+		t.Errorf("Verified summary: %+v", v)
+	}
+}
+
+func TestNewReviewInput(t *testing.T) {
+	var change gerrit.ChangeInfo
+	if err := json.Unmarshal([]byte(detailedLabelsChange), &change); err != nil {
+		t.Fatal(err)
+	}
+
+	if !change.CanVote("Code-Review", 1) || change.CanVote("Code-Review", 2) || change.CanVote("Library-Compliance", 1) {
+		t.Error("CanVote() returned unexpected results")
+	}
+
+	input, err := gerrit.NewReviewInput(&change, map[string]int{"Code-Review": 1, "Verified": -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if input.Labels["Code-Review"] != 1 || input.Labels["Verified"] != -1 {
+		t.Errorf("Unexpected labels %+v", input.Labels)
+	}
+
+	if _, err := gerrit.NewReviewInput(&change, map[string]int{"Code-Review": 2}); err == nil {
+		t.Error("Expected error for out of range vote")
+	}
+	if _, err := gerrit.NewReviewInput(&change, map[string]int{"Library-Compliance": 1}); err == nil {
+		t.Error("Expected error for label that is not permitted")
+	}
+}
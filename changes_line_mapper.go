@@ -0,0 +1,298 @@
+package gerrit
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+)
+
+// LineStatus describes what happened to a line between the two sides of a diff.
+type LineStatus string
+
+const (
+	// LineUnchanged means the line kept its content and line number.
+	LineUnchanged LineStatus = "UNCHANGED"
+	// LineMoved means the line kept its content, but moved because lines were added or removed before it.
+	LineMoved LineStatus = "MOVED"
+	// LineModified means the line was replaced by other lines.
+	LineModified LineStatus = "MODIFIED"
+	// LineDeleted means the line was removed without replacement.
+	LineDeleted LineStatus = "DELETED"
+)
+
+// lineStatusSeverity orders the statuses, so that the status of a range is the worst status of its lines.
+var lineStatusSeverity = map[LineStatus]int{LineUnchanged: 0, LineMoved: 1, LineModified: 2, LineDeleted: 3}
+
+// LineMapper translates line numbers of the old side of a diff (A) to the new side (B).
+// It is built from a DiffInfo, e.g. as returned by GetDiff with DiffOptions.Base set
+// to an older patch set.
+// ListPortedCommentsWithFallback and ListPortedDraftsWithFallback use it to port comments.
+type LineMapper struct {
+	chunks []lineChunk
+	endA   int
+	endB   int
+	// newPath is the path of the file on side B if the file was renamed or copied.
+	newPath string
+}
+
+// lineChunk is a region of a diff. Lines are 1-based, end is exclusive.
+type lineChunk struct {
+	startA, endA int
+	startB, endB int
+	changed      bool
+}
+
+// NewLineMapper builds a LineMapper from the content of diff.
+func NewLineMapper(diff *DiffInfo) *LineMapper {
+	m := &LineMapper{endA: 1, endB: 1}
+	if diff.ChangeType == "RENAMED" || diff.ChangeType == "COPIED" {
+		m.newPath = diff.MetaB.Name
+	}
+	for _, c := range diff.Content {
+		var a, b int
+		changed := false
+		switch {
+		case c.Skip > 0:
+			a, b = c.Skip, c.Skip
+		case len(c.AB) > 0:
+			a, b = len(c.AB), len(c.AB)
+		default:
+			a, b = len(c.A), len(c.B)
+			changed = true
+		}
+		m.chunks = append(m.chunks, lineChunk{
+			startA: m.endA, endA: m.endA + a,
+			startB: m.endB, endB: m.endB + b,
+			changed: changed,
+		})
+		m.endA += a
+		m.endB += b
+	}
+	return m
+}
+
+// GetLineMapper builds a LineMapper that translates the lines of a file from one patch set
+// of a change to another, using GetDiff.
+func (s *ChangesService) GetLineMapper(ctx context.Context, changeID, filePath string, fromPatchSet, toPatchSet int) (*LineMapper, *Response, error) {
+	diff, resp, err := s.GetDiff(ctx, changeID, strconv.Itoa(toPatchSet), filePath, &DiffOptions{Base: strconv.Itoa(fromPatchSet)})
+	if err != nil {
+		return nil, resp, err
+	}
+	return NewLineMapper(diff), resp, nil
+}
+
+// MapLine translates line on side A to side B.
+// The second return value is false if the line was deleted or modified.
+// In this case the returned line is the first line on side B that replaced it,
+// which is a good place to anchor a comment on.
+// Lines beyond the end of the diff are shifted by the total line delta.
+func (m *LineMapper) MapLine(line int) (int, bool) {
+	mapped, status := m.MapLineStatus(line)
+	return mapped, status == LineUnchanged || status == LineMoved
+}
+
+// MapLineStatus translates line on side A to side B like MapLine,
+// but reports in detail what happened to the line.
+func (m *LineMapper) MapLineStatus(line int) (int, LineStatus) {
+	for _, c := range m.chunks {
+		if line < c.startA || line >= c.endA {
+			continue
+		}
+		if c.changed {
+			if c.endB > c.startB {
+				return c.startB, LineModified
+			}
+			return c.startB, LineDeleted
+		}
+		return c.startB + line - c.startA, movedStatus(line, c.startB+line-c.startA)
+	}
+	mapped := line + m.endB - m.endA
+	return mapped, movedStatus(line, mapped)
+}
+
+func movedStatus(line, mapped int) LineStatus {
+	if line == mapped {
+		return LineUnchanged
+	}
+	return LineMoved
+}
+
+// MapRange translates r on side A to side B.
+// The returned status is the worst status of all lines within r.
+// If any of the lines was modified or deleted, the returned range covers the lines on side B
+// that replaced the start and end line, and the characters are reset to cover whole lines.
+func (m *LineMapper) MapRange(r *CommentRange) (*CommentRange, LineStatus) {
+	status := LineUnchanged
+	for line := r.StartLine; line <= r.EndLine; line++ {
+		if _, s := m.MapLineStatus(line); lineStatusSeverity[s] > lineStatusSeverity[status] {
+			status = s
+		}
+	}
+
+	start, _ := m.MapLineStatus(r.StartLine)
+	end, _ := m.MapLineStatus(r.EndLine)
+	mapped := &CommentRange{StartLine: start, StartCharacter: r.StartCharacter, EndLine: end, EndCharacter: r.EndCharacter}
+	if status == LineModified || status == LineDeleted {
+		mapped.StartCharacter, mapped.EndCharacter = 0, 0
+	}
+	return mapped, status
+}
+
+// MapCommentInput translates the position of a comment on side A to side B,
+// e.g. to re-post a finding of an older patch set on the current patch set.
+// The path is updated if the file was renamed or copied.
+//
+// If the returned status is LineModified or LineDeleted, the commented code is gone;
+// the returned comment is anchored on the code that replaced it, but the finding
+// may rather be resolved.
+// Comments on the file itself and comments on the parent side are returned unchanged.
+func (m *LineMapper) MapCommentInput(c *CommentInput) (*CommentInput, LineStatus) {
+	mapped := *c
+	if m.newPath != "" && mapped.Path != "" {
+		mapped.Path = m.newPath
+	}
+	if c.Side == "PARENT" || (c.Line == 0 && c.Range == nil) {
+		return &mapped, LineUnchanged
+	}
+
+	status := LineUnchanged
+	if c.Range != nil {
+		mapped.Range, status = m.MapRange(c.Range)
+	}
+	if c.Line > 0 {
+		var lineStatus LineStatus
+		mapped.Line, lineStatus = m.MapLineStatus(c.Line)
+		if c.Range != nil {
+			// The line of a comment with a range is its end line.
+			mapped.Line = mapped.Range.EndLine
+		}
+		if lineStatusSeverity[lineStatus] > lineStatusSeverity[status] {
+			status = lineStatus
+		}
+	}
+	return &mapped, status
+}
+
+// ListPortedCommentsWithFallback lists the unresolved comments of the change ported to the given patch set,
+// like ListPortedComments.
+//
+// If the server doesn't support ported comments, they are computed client-side:
+// the unresolved threads of older patch sets are fetched with ListChangeComments and
+// their lines are mapped with GetDiff against the patch set of each comment.
+// Comments whose lines were deleted or modified are ported to the file level,
+// as are the comments of files whose diff doesn't exist (404) or can't be computed (409),
+// e.g. because the file was deleted. Other errors of GetDiff are returned.
+// Comments on the parent side keep their position.
+// Like with ListPortedComments, the patch_set field of each comment is not changed.
+func (s *ChangesService) ListPortedCommentsWithFallback(ctx context.Context, changeID string, patchSet int) (*map[string][]CommentInfo, *Response, error) {
+	v, resp, err := s.ListPortedComments(ctx, changeID, strconv.Itoa(patchSet))
+	if err == nil || resp == nil || resp.StatusCode != http.StatusNotFound {
+		return v, resp, err
+	}
+
+	comments, resp, err := s.ListChangeComments(ctx, changeID)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var threads []CommentThread
+	for _, t := range NewCommentThreads(*comments, nil, nil) {
+		if t.Unresolved() {
+			threads = append(threads, t)
+		}
+	}
+	ported, err := s.portThreads(ctx, changeID, patchSet, threads)
+	if err != nil {
+		return nil, resp, err
+	}
+	return ported, resp, nil
+}
+
+// ListPortedDraftsWithFallback lists the draft comments of the calling user ported to the given patch set,
+// like ListPortedDrafts.
+//
+// If the server doesn't support ported drafts, they are computed client-side from ListChangeDrafts
+// the same way ListPortedCommentsWithFallback does for published comments.
+func (s *ChangesService) ListPortedDraftsWithFallback(ctx context.Context, changeID string, patchSet int) (*map[string][]CommentInfo, *Response, error) {
+	v, resp, err := s.ListPortedDrafts(ctx, changeID, strconv.Itoa(patchSet))
+	if err == nil || resp == nil || resp.StatusCode != http.StatusNotFound {
+		return v, resp, err
+	}
+
+	drafts, resp, err := s.ListChangeDrafts(ctx, changeID)
+	if err != nil {
+		return nil, resp, err
+	}
+	ported, err := s.portThreads(ctx, changeID, patchSet, NewCommentThreads(nil, *drafts, nil))
+	if err != nil {
+		return nil, resp, err
+	}
+	return ported, resp, nil
+}
+
+// portThreads ports the comments of the threads on patch sets older than patchSet to patchSet.
+func (s *ChangesService) portThreads(ctx context.Context, changeID string, patchSet int, threads []CommentThread) (*map[string][]CommentInfo, error) {
+	revisionID := strconv.Itoa(patchSet)
+	ported := map[string][]CommentInfo{}
+	mappers := map[string]*LineMapper{}
+	for _, t := range threads {
+		if t.PatchSet >= patchSet {
+			continue
+		}
+
+		var mapper *LineMapper
+		positioned := t.Side != "PARENT" && (t.Line > 0 || t.Range != nil)
+		if positioned {
+			key := strconv.Itoa(t.PatchSet) + ":" + t.Path
+			var ok bool
+			if mapper, ok = mappers[key]; !ok {
+				diff, resp, err := s.GetDiff(ctx, changeID, revisionID, t.Path, &DiffOptions{Base: strconv.Itoa(t.PatchSet)})
+				switch {
+				case err == nil:
+					mapper = NewLineMapper(diff)
+				case resp != nil && (resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusConflict):
+					// The diff is unavailable, e.g. because the file was deleted,
+					// so the comments of the file are ported to the file level.
+				default:
+					return nil, err
+				}
+				mappers[key] = mapper
+			}
+		}
+
+		for _, c := range t.Comments() {
+			pc := c.CommentInfo
+			if mapper != nil {
+				portCommentPosition(&pc, mapper)
+			} else if positioned {
+				pc.Line, pc.Range = 0, nil
+			}
+			ported[pc.Path] = append(ported[pc.Path], pc)
+		}
+	}
+	return &ported, nil
+}
+
+// portCommentPosition moves the line and range of c with m.
+// If the position can't be mapped, c becomes a file comment.
+func portCommentPosition(c *CommentInfo, m *LineMapper) {
+	if c.Range != nil {
+		start, okStart := m.MapLine(c.Range.StartLine)
+		end, okEnd := m.MapLine(c.Range.EndLine)
+		if !okStart || !okEnd {
+			c.Line, c.Range = 0, nil
+			return
+		}
+		r := *c.Range
+		r.StartLine, r.EndLine = start, end
+		c.Range = &r
+	}
+	if c.Line > 0 {
+		line, ok := m.MapLine(c.Line)
+		if !ok {
+			c.Line, c.Range = 0, nil
+			return
+		}
+		c.Line = line
+	}
+}
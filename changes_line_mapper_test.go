@@ -0,0 +1,237 @@
+package gerrit_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/andygrunwald/go-gerrit"
+)
+
+// lineMapperDiff turns
+//
+//	1 a      1 a
+//	2 b      2 b
+//	3 c   -> 3 X
+//	4 d      4 Y
+//	5 e      5 d
+//	         6 e
+//	...      ...
+//	15 o     16 o
+//	16 p
+//
+// where the unchanged lines 6-15 are skipped.
+var lineMapperDiff = &gerrit.DiffInfo{
+	Content: []gerrit.DiffContent{
+		{AB: []string{"a", "b"}},
+		{A: []string{"c"}, B: []string{"X", "Y"}},
+		{AB: []string{"d", "e"}},
+		{Skip: 10},
+		{A: []string{"p"}},
+	},
+}
+
+func TestLineMapper_MapLine(t *testing.T) {
+	m := gerrit.NewLineMapper(lineMapperDiff)
+	tests := []struct {
+		line int
+		want int
+		ok   bool
+	}{
+		{1, 1, true},
+		{2, 2, true},
+		{3, 3, false},
+		{4, 5, true},
+		{5, 6, true},
+		{10, 11, true},
+		{15, 16, true},
+		{16, 17, false},
+	}
+	for _, tt := range tests {
+		got, ok := m.MapLine(tt.line)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("MapLine(%d) = %d, %t; want %d, %t", tt.line, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestChangesService_ListPortedCommentsWithFallback(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/changes/123/revisions/3/ported_comments", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	testMux.HandleFunc("/changes/123/comments", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `)]}'
+{
+  "foo.go": [
+    {"id": "c1", "patch_set": 1, "line": 4, "message": "rename this", "unresolved": true, "updated": "2020-01-01 10:00:00.000000000"},
+    {"id": "c2", "patch_set": 1, "line": 4, "in_reply_to": "c1", "message": "why?", "unresolved": true, "updated": "2020-01-01 11:00:00.000000000"},
+    {"id": "c3", "patch_set": 1, "line": 3, "message": "typo", "unresolved": true, "updated": "2020-01-01 10:00:00.000000000"},
+    {"id": "c4", "patch_set": 1, "line": 1, "message": "resolved", "unresolved": false, "updated": "2020-01-01 10:00:00.000000000"},
+    {"id": "c5", "patch_set": 3, "line": 1, "message": "current", "unresolved": true, "updated": "2020-01-01 10:00:00.000000000"}
+  ],
+  "gone.go": [
+    {"id": "g1", "patch_set": 2, "line": 7, "message": "deleted later", "unresolved": true, "updated": "2020-01-01 10:00:00.000000000"}
+  ]
+}`)
+	})
+	testMux.HandleFunc("/changes/123/revisions/3/files/gone.go/diff", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	testMux.HandleFunc("/changes/123/revisions/3/files/foo.go/diff", func(w http.ResponseWriter, r *http.Request) {
+		testQueryValues(t, r, testValues{"base": "1"})
+		writeresponse(t, w, lineMapperDiff, http.StatusOK)
+	})
+
+	ctx := context.Background()
+	got, _, err := testClient.Changes.ListPortedCommentsWithFallback(ctx, "123", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var lines []string
+	for _, c := range (*got)["foo.go"] {
+		lines = append(lines, fmt.Sprintf("%s:%d", c.ID, c.Line))
+	}
+	want := []string{"c3:0", "c1:5", "c2:5"}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("ported comments:\ngot:  %v\nwant: %v", lines, want)
+	}
+	if gone := (*got)["gone.go"]; len(gone) != 1 || gone[0].Line != 0 {
+		t.Errorf("comments of deleted file not ported to file level: %+v", gone)
+	}
+}
+
+func TestChangesService_ListPortedCommentsWithFallback_DiffError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/changes/123/revisions/3/ported_comments", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	testMux.HandleFunc("/changes/123/comments", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `)]}'
+{"foo.go": [{"id": "c1", "patch_set": 1, "line": 4, "message": "rename this", "unresolved": true}]}`)
+	})
+	testMux.HandleFunc("/changes/123/revisions/3/files/foo.go/diff", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+	})
+
+	if _, _, err := testClient.Changes.ListPortedCommentsWithFallback(context.Background(), "123", 3); err == nil {
+		t.Error("ListPortedCommentsWithFallback() succeeded, want the error of GetDiff")
+	}
+}
+
+func TestChangesService_ListPortedDraftsWithFallback(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/changes/123/revisions/3/ported_drafts", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	testMux.HandleFunc("/changes/123/drafts", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `)]}'
+{
+  "foo.go": [
+    {"id": "d1", "patch_set": 1, "line": 5, "message": "draft", "unresolved": false, "updated": "2020-01-01 10:00:00.000000000"},
+    {"id": "d2", "patch_set": 3, "line": 1, "message": "current", "unresolved": true, "updated": "2020-01-01 10:00:00.000000000"}
+  ]
+}`)
+	})
+	testMux.HandleFunc("/changes/123/revisions/3/files/foo.go/diff", func(w http.ResponseWriter, r *http.Request) {
+		testQueryValues(t, r, testValues{"base": "1"})
+		writeresponse(t, w, lineMapperDiff, http.StatusOK)
+	})
+
+	got, _, err := testClient.Changes.ListPortedDraftsWithFallback(context.Background(), "123", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if drafts := (*got)["foo.go"]; len(drafts) != 1 || drafts[0].ID != "d1" || drafts[0].Line != 6 {
+		t.Errorf("Unexpected ported drafts %+v", drafts)
+	}
+}
+
+func TestLineMapper_MapCommentInput(t *testing.T) {
+	diff := &gerrit.DiffInfo{
+		ChangeType: "RENAMED",
+		MetaA:      gerrit.DiffFileMetaInfo{Name: "old.go"},
+		MetaB:      gerrit.DiffFileMetaInfo{Name: "new.go"},
+		Content:    lineMapperDiff.Content,
+	}
+	m := gerrit.NewLineMapper(diff)
+
+	tests := []struct {
+		name   string
+		input  gerrit.CommentInput
+		want   gerrit.CommentInput
+		status gerrit.LineStatus
+	}{
+		{
+			name:   "unchanged line",
+			input:  gerrit.CommentInput{Path: "old.go", Line: 2},
+			want:   gerrit.CommentInput{Path: "new.go", Line: 2},
+			status: gerrit.LineUnchanged,
+		},
+		{
+			name:   "moved range",
+			input:  gerrit.CommentInput{Path: "old.go", Line: 5, Range: &gerrit.CommentRange{StartLine: 4, StartCharacter: 1, EndLine: 5, EndCharacter: 3}},
+			want:   gerrit.CommentInput{Path: "new.go", Line: 6, Range: &gerrit.CommentRange{StartLine: 5, StartCharacter: 1, EndLine: 6, EndCharacter: 3}},
+			status: gerrit.LineMoved,
+		},
+		{
+			name:   "range over modified line",
+			input:  gerrit.CommentInput{Path: "old.go", Line: 4, Range: &gerrit.CommentRange{StartLine: 2, StartCharacter: 1, EndLine: 4, EndCharacter: 3}},
+			want:   gerrit.CommentInput{Path: "new.go", Line: 5, Range: &gerrit.CommentRange{StartLine: 2, EndLine: 5}},
+			status: gerrit.LineModified,
+		},
+		{
+			name:   "modified line",
+			input:  gerrit.CommentInput{Path: "old.go", Line: 3},
+			want:   gerrit.CommentInput{Path: "new.go", Line: 3},
+			status: gerrit.LineModified,
+		},
+		{
+			name:   "deleted line",
+			input:  gerrit.CommentInput{Path: "old.go", Line: 16},
+			want:   gerrit.CommentInput{Path: "new.go", Line: 17},
+			status: gerrit.LineDeleted,
+		},
+		{
+			name:   "file comment",
+			input:  gerrit.CommentInput{Path: "old.go", Message: "Add tests"},
+			want:   gerrit.CommentInput{Path: "new.go", Message: "Add tests"},
+			status: gerrit.LineUnchanged,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, status := m.MapCommentInput(&tt.input)
+			if !reflect.DeepEqual(*got, tt.want) || status != tt.status {
+				t.Errorf("MapCommentInput() = %+v (range %+v), %s; want %+v (range %+v), %s", *got, got.Range, status, tt.want, tt.want.Range, tt.status)
+			}
+		})
+	}
+}
+
+func TestChangesService_GetLineMapper(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/changes/123/revisions/3/files/main.go/diff", func(w http.ResponseWriter, r *http.Request) {
+		testFormValues(t, r, testValues{"base": "2"})
+		fmt.Fprint(w, `{"content": [{"b": ["// Package main"]}, {"ab": ["package main"]}]}`)
+	})
+
+	m, _, err := testClient.Changes.GetLineMapper(context.Background(), "123", "main.go", 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if line, status := m.MapLineStatus(1); line != 2 || status != gerrit.LineMoved {
+		t.Errorf("MapLineStatus(1) = %d, %s; want 2, %s", line, status, gerrit.LineMoved)
+	}
+}
@@ -0,0 +1,110 @@
+package gerrit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// AutogeneratedTagPrefix is the prefix of tags that Gerrit and well-behaved bots use for
+// change messages that were not written by a human.
+//
+// Gerrit docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#review-input
+const AutogeneratedTagPrefix = "autogenerated:"
+
+// DeleteChangeMessageInput entity contains the options for deleting a change message.
+//
+// Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#delete-change-message-input
+type DeleteChangeMessageInput struct {
+	// The reason why the change message should be deleted.
+	// If set, the change message will be replaced with "Change message removed by: name\nReason: reason",
+	// or just "Change message removed by: name" if not set.
+	Reason string `json:"reason,omitempty"`
+}
+
+// ListChangeMessages lists all the messages of a change including detailed account information.
+// As response a list of ChangeMessageInfo entities is returned.
+//
+// Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#list-change-messages
+func (s *ChangesService) ListChangeMessages(ctx context.Context, changeID string) ([]ChangeMessageInfo, *Response, error) {
+	u := fmt.Sprintf("changes/%s/messages", changeID)
+
+	req, err := s.client.NewRequest(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var v []ChangeMessageInfo
+	resp, err := s.client.Do(req, &v)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return v, resp, err
+}
+
+// GetChangeMessage retrieves a change message including detailed account information.
+//
+// Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#get-change-message
+func (s *ChangesService) GetChangeMessage(ctx context.Context, changeID, messageID string) (*ChangeMessageInfo, *Response, error) {
+	u := fmt.Sprintf("changes/%s/messages/%s", changeID, messageID)
+
+	req, err := s.client.NewRequest(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	v := new(ChangeMessageInfo)
+	resp, err := s.client.Do(req, v)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return v, resp, err
+}
+
+// DeleteChangeMessage deletes a change message by replacing it with a new message
+// that contains the name of the user who deleted the change message and the reason why it was deleted.
+// This endpoint requires the Administrate Server global capability.
+//
+// As response a ChangeMessageInfo entity is returned that describes the updated change message.
+//
+// Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#delete-change-message
+func (s *ChangesService) DeleteChangeMessage(ctx context.Context, changeID, messageID string, input *DeleteChangeMessageInput) (*ChangeMessageInfo, *Response, error) {
+	// POST is used instead of DELETE, because some proxies prohibit request bodies for DELETE requests.
+	u := fmt.Sprintf("changes/%s/messages/%s/delete", changeID, messageID)
+
+	req, err := s.client.NewRequest(ctx, "POST", u, input)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	v := new(ChangeMessageInfo)
+	resp, err := s.client.Do(req, v)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return v, resp, err
+}
+
+// IsAutogenerated reports whether the message is tagged as autogenerated,
+// e.g. by a bot or by Gerrit itself when a new patch set was uploaded.
+func (m *ChangeMessageInfo) IsAutogenerated() bool {
+	return strings.HasPrefix(m.Tag, AutogeneratedTagPrefix)
+}
+
+// ChangeMessagesByTag returns the messages whose tag starts with tagPrefix.
+// The order of messages is preserved.
+//
+// For example, ChangeMessagesByTag(messages, "autogenerated:ci") returns
+// all messages posted by a CI system that tags its messages accordingly.
+func ChangeMessagesByTag(messages []ChangeMessageInfo, tagPrefix string) []ChangeMessageInfo {
+	var result []ChangeMessageInfo
+	for _, m := range messages {
+		if m.Tag != "" && strings.HasPrefix(m.Tag, tagPrefix) {
+			result = append(result, m)
+		}
+	}
+	return result
+}
@@ -0,0 +1,118 @@
+package gerrit_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andygrunwald/go-gerrit"
+)
+
+func TestChangesService_ListChangeMessages(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, "/changes/123/messages")
+
+		_, err := fmt.Fprint(w, `)]}'
+[
+  {
+    "id": "YH-egE",
+    "author": {"_account_id": 1000096, "name": "John Doe"},
+    "date": "2013-03-23 21:34:02.419000000",
+    "message": "Patch Set 1:\n\nThis is the first message.",
+    "_revision_number": 1
+  },
+  {
+    "id": "WEEdhU",
+    "author": {"_account_id": 1000097, "name": "CI Bot"},
+    "real_author": {"_account_id": 1000098, "name": "Jane Roe"},
+    "date": "2013-03-23 21:36:52.332000000",
+    "message": "Patch Set 1: Verified+1",
+    "tag": "autogenerated:ci",
+    "_revision_number": 1
+  }
+]`)
+		if err != nil {
+			t.Error(err)
+		}
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	client := newClient(ctx, t, ts)
+	got, _, err := client.Changes.ListChangeMessages(ctx, "123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("Unexpected messages %+v", got)
+	}
+	if got[1].RealAuthor.AccountID != 1000098 {
+		t.Errorf("RealAuthor = %+v, want account 1000098", got[1].RealAuthor)
+	}
+	if got[0].IsAutogenerated() || !got[1].IsAutogenerated() {
+		t.Error("IsAutogenerated() returned unexpected results")
+	}
+	if bots := gerrit.ChangeMessagesByTag(got, "autogenerated:ci"); len(bots) != 1 || bots[0].ID != "WEEdhU" {
+		t.Errorf("ChangeMessagesByTag() = %+v", bots)
+	}
+}
+
+func TestChangesService_GetChangeMessage(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, "/changes/123/messages/YH-egE")
+
+		_, err := fmt.Fprint(w, `)]}'
+{"id": "YH-egE", "message": "Patch Set 1:\n\nThis is the first message."}`)
+		if err != nil {
+			t.Error(err)
+		}
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	client := newClient(ctx, t, ts)
+	got, _, err := client.Changes.GetChangeMessage(ctx, "123", "YH-egE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != "YH-egE" {
+		t.Errorf("ID = %q, want %q", got.ID, "YH-egE")
+	}
+}
+
+func TestChangesService_DeleteChangeMessage(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testRequestURL(t, r, "/changes/123/messages/YH-egE/delete")
+
+		var input gerrit.DeleteChangeMessageInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			t.Error(err)
+		}
+		if input.Reason != "Contains a password" {
+			t.Errorf("Reason = %q", input.Reason)
+		}
+
+		_, err := fmt.Fprint(w, `)]}'
+{"id": "YH-egE", "message": "Change message removed by: Administrator\nReason: Contains a password"}`)
+		if err != nil {
+			t.Error(err)
+		}
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	client := newClient(ctx, t, ts)
+	got, _, err := client.Changes.DeleteChangeMessage(ctx, "123", "YH-egE", &gerrit.DeleteChangeMessageInput{Reason: "Contains a password"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Change message removed by: Administrator\nReason: Contains a password"; got.Message != want {
+		t.Errorf("Message = %q, want %q", got.Message, want)
+	}
+}
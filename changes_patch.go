@@ -0,0 +1,339 @@
+package gerrit
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/mail"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Patch is a formatted patch of a revision as returned by GetPatch, parsed.
+type Patch struct {
+	// Commit is the SHA-1 of the commit the patch was created from.
+	Commit  string
+	Author  GitPersonInfo
+	Subject string
+	// Message is the full commit message, including the subject.
+	Message string
+	Files   []PatchFile
+}
+
+// PatchFile is the diff of a single file within a Patch.
+type PatchFile struct {
+	// OldPath and NewPath are the paths of the file before and after the change.
+	// OldPath is empty for added files, NewPath is empty for deleted files.
+	OldPath string
+	NewPath string
+	// ChangeType is one of "ADDED", "MODIFIED", "DELETED", "RENAMED" or "COPIED",
+	// like DiffInfo.ChangeType.
+	ChangeType string
+	Binary     bool
+	Hunks      []PatchHunk
+}
+
+// PatchHunk is a hunk of a PatchFile.
+type PatchHunk struct {
+	OldStart, OldLines int
+	NewStart, NewLines int
+	// Section is the text after the hunk header, usually the enclosing function.
+	Section string
+	// Lines are the lines of the hunk including their prefix (" ", "-", "+" or "\").
+	Lines []string
+}
+
+// WritePatch writes the formatted patch of a revision to w as plain text.
+//
+// The patch is decoded from base64 while it is received.
+// If opt.Zip is set, the patch is downloaded as ZIP archive and unpacked instead.
+//
+// Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#get-patch
+func (s *ChangesService) WritePatch(ctx context.Context, changeID, revisionID string, opt *PatchOptions, w io.Writer) (*Response, error) {
+	u := fmt.Sprintf("changes/%s/revisions/%s/patch", changeID, revisionID)
+
+	u, err := addOptions(u, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if opt != nil && opt.Zip {
+		var buf bytes.Buffer
+		resp, err := s.client.Do(req, &buf)
+		if err != nil {
+			return resp, err
+		}
+		return resp, unzipPatch(buf.Bytes(), w)
+	}
+
+	pr, pw := io.Pipe()
+	decoded := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(w, base64.NewDecoder(base64.StdEncoding, pr))
+		pr.CloseWithError(err)
+		decoded <- err
+	}()
+	resp, err := s.client.Do(req, pw)
+	pw.CloseWithError(err)
+	if decodeErr := <-decoded; err == nil && decodeErr != nil {
+		err = fmt.Errorf("decoding patch: %v", decodeErr)
+	}
+	return resp, err
+}
+
+// unzipPatch writes the single file of a patch ZIP archive to w.
+func unzipPatch(data []byte, w io.Writer) error {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return fmt.Errorf("unpacking patch: %v", err)
+	}
+	if len(r.File) != 1 {
+		return fmt.Errorf("unpacking patch: archive contains %d files, expected 1", len(r.File))
+	}
+	f, err := r.File[0].Open()
+	if err != nil {
+		return fmt.Errorf("unpacking patch: %v", err)
+	}
+	defer f.Close() // nolint: errcheck
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// GetParsedPatch gets the formatted patch of a revision, decodes and parses it.
+//
+// Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#get-patch
+func (s *ChangesService) GetParsedPatch(ctx context.Context, changeID, revisionID string, opt *PatchOptions) (*Patch, *Response, error) {
+	var buf bytes.Buffer
+	resp, err := s.WritePatch(ctx, changeID, revisionID, opt, &buf)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	v, err := ParsePatch(buf.String())
+	if err != nil {
+		return nil, resp, err
+	}
+	return v, resp, err
+}
+
+var (
+	// ErrInvalidPatch is returned by ParsePatch if the text is not a formatted patch.
+	ErrInvalidPatch = errors.New("invalid patch: missing mbox header")
+
+	// patchFromLinePattern matches the first line of a formatted patch.
+	patchFromLinePattern = regexp.MustCompile(`^From ([0-9a-f]{40}) `)
+	// patchSubjectPrefixPattern matches the prefix git adds to the subject of a formatted patch.
+	patchSubjectPrefixPattern = regexp.MustCompile(`^\[PATCH[^\]]*\]\s*`)
+	// hunkHeaderPattern matches the header of a hunk.
+	hunkHeaderPattern = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@ ?(.*)$`)
+)
+
+// ParsePatch parses a formatted patch in the mbox format of "git format-patch",
+// as returned by GetPatch once decoded.
+func ParsePatch(text string) (*Patch, error) {
+	i := strings.IndexByte(text, '\n')
+	if i < 0 {
+		return nil, ErrInvalidPatch
+	}
+	m := patchFromLinePattern.FindStringSubmatch(text[:i])
+	if m == nil {
+		return nil, ErrInvalidPatch
+	}
+	p := &Patch{Commit: m[1]}
+
+	msg, err := mail.ReadMessage(strings.NewReader(text[i+1:]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid patch: %v", err)
+	}
+
+	dec := new(mime.WordDecoder)
+	if from := msg.Header.Get("From"); from != "" {
+		addr, err := mail.ParseAddress(from)
+		if err != nil {
+			return nil, fmt.Errorf("invalid patch author %q: %v", from, err)
+		}
+		p.Author.Name, p.Author.Email = addr.Name, addr.Address
+	}
+	if date := msg.Header.Get("Date"); date != "" {
+		t, err := mail.ParseDate(date)
+		if err != nil {
+			return nil, fmt.Errorf("invalid patch date %q: %v", date, err)
+		}
+		_, offset := t.Zone()
+		p.Author.Date = Timestamp{t.UTC()}
+		p.Author.TZ = offset / 60
+	}
+	subject, err := dec.DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid patch subject: %v", err)
+	}
+	p.Subject = patchSubjectPrefixPattern.ReplaceAllString(subject, "")
+
+	body, err := io.ReadAll(msg.Body)
+	if err != nil {
+		return nil, err
+	}
+	description, diff := splitPatchBody(string(body))
+	p.Message = p.Subject + "\n"
+	if description != "" {
+		p.Message += "\n" + description + "\n"
+	}
+
+	p.Files, err = parsePatchFiles(diff)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// splitPatchBody splits the body of a formatted patch into the commit message body and the diff.
+func splitPatchBody(body string) (string, string) {
+	var description []string
+	lines := strings.SplitAfter(body, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimRight(line, "\r\n")
+		if trimmed == "---" || strings.HasPrefix(trimmed, "diff --git ") {
+			if trimmed == "---" {
+				i++
+			}
+			return strings.TrimSpace(strings.Join(description, "")), strings.Join(lines[i:], "")
+		}
+		description = append(description, line)
+	}
+	return strings.TrimSpace(body), ""
+}
+
+// parsePatchFiles parses the file diffs of a formatted patch.
+// Everything before the first "diff --git" line, like a diffstat, is ignored.
+func parsePatchFiles(diff string) ([]PatchFile, error) {
+	var files []PatchFile
+	var file *PatchFile
+	var hunk *PatchHunk
+	oldLeft, newLeft := 0, 0
+
+	sc := bufio.NewScanner(strings.NewReader(diff))
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		line := sc.Text()
+
+		if hunk != nil && (oldLeft > 0 || newLeft > 0 || strings.HasPrefix(line, `\`)) {
+			switch {
+			case strings.HasPrefix(line, "+"):
+				newLeft--
+			case strings.HasPrefix(line, "-"):
+				oldLeft--
+			case strings.HasPrefix(line, `\`):
+			default:
+				oldLeft--
+				newLeft--
+			}
+			hunk.Lines = append(hunk.Lines, line)
+			continue
+		}
+		hunk = nil
+
+		if strings.HasPrefix(line, "diff --git ") {
+			files = append(files, PatchFile{ChangeType: "MODIFIED"})
+			file = &files[len(files)-1]
+			file.OldPath, file.NewPath = parseDiffGitPaths(strings.TrimPrefix(line, "diff --git "))
+			continue
+		}
+		if file == nil {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "new file mode"):
+			file.ChangeType = "ADDED"
+			file.OldPath = ""
+		case strings.HasPrefix(line, "deleted file mode"):
+			file.ChangeType = "DELETED"
+			file.NewPath = ""
+		case strings.HasPrefix(line, "rename from "):
+			file.ChangeType = "RENAMED"
+			file.OldPath = strings.TrimPrefix(line, "rename from ")
+		case strings.HasPrefix(line, "rename to "):
+			file.NewPath = strings.TrimPrefix(line, "rename to ")
+		case strings.HasPrefix(line, "copy from "):
+			file.ChangeType = "COPIED"
+			file.OldPath = strings.TrimPrefix(line, "copy from ")
+		case strings.HasPrefix(line, "copy to "):
+			file.NewPath = strings.TrimPrefix(line, "copy to ")
+		case strings.HasPrefix(line, "Binary files ") || line == "GIT binary patch":
+			file.Binary = true
+		case strings.HasPrefix(line, "--- "):
+			file.OldPath = patchFilePath(strings.TrimPrefix(line, "--- "), "a/")
+		case strings.HasPrefix(line, "+++ "):
+			file.NewPath = patchFilePath(strings.TrimPrefix(line, "+++ "), "b/")
+		case strings.HasPrefix(line, "@@ "):
+			m := hunkHeaderPattern.FindStringSubmatch(line)
+			if m == nil {
+				return nil, fmt.Errorf("invalid hunk header %q", line)
+			}
+			h := PatchHunk{Section: m[5]}
+			h.OldStart, _ = strconv.Atoi(m[1])
+			h.OldLines = hunkLineCount(m[2])
+			h.NewStart, _ = strconv.Atoi(m[3])
+			h.NewLines = hunkLineCount(m[4])
+			file.Hunks = append(file.Hunks, h)
+			hunk = &file.Hunks[len(file.Hunks)-1]
+			oldLeft, newLeft = h.OldLines, h.NewLines
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if hunk != nil && (oldLeft > 0 || newLeft > 0) {
+		return nil, fmt.Errorf("truncated hunk in %s", files[len(files)-1].NewPath)
+	}
+	return files, nil
+}
+
+// parseDiffGitPaths splits the paths of a "diff --git a/x b/y" line.
+// The paths are ambiguous if they contain " b/", but they are refined by later header lines.
+func parseDiffGitPaths(s string) (string, string) {
+	if !strings.HasPrefix(s, "a/") {
+		return "", ""
+	}
+	// Both paths are usually the same, so try to split in the middle first.
+	if n := len(s); n%2 == 1 && s[n/2] == ' ' && s[2:n/2] == s[n/2+3:] {
+		return s[2 : n/2], s[n/2+3:]
+	}
+	i := strings.Index(s, " b/")
+	if i < 0 {
+		return "", ""
+	}
+	return s[2:i], s[i+3:]
+}
+
+// patchFilePath strips the prefix of the path of a "---" or "+++" line.
+func patchFilePath(s, prefix string) string {
+	if i := strings.IndexByte(s, '\t'); i >= 0 {
+		s = s[:i]
+	}
+	if s == "/dev/null" {
+		return ""
+	}
+	return strings.TrimPrefix(s, prefix)
+}
+
+// hunkLineCount parses the line count of a hunk header, which defaults to 1.
+func hunkLineCount(s string) int {
+	if s == "" {
+		return 1
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
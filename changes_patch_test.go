@@ -0,0 +1,160 @@
+package gerrit_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/andygrunwald/go-gerrit"
+)
+
+const testPatch = `From 674ac754f91e64a0efb8087e59a176484bd534d1 Mon Sep 17 00:00:00 2001
+From: =?UTF-8?q?J=C3=BCrgen=20Doe?= <jdoe@example.com>
+Date: Tue, 02 Jun 2015 15:36:07 +0200
+Subject: [PATCH] Use an explicit flag for the new
+ behaviour
+
+The old heuristic was wrong.
+
+Change-Id: I8473b95934b5732ac55d26311a706c9c2bde9940
+---
+
+diff --git a/main.go b/main.go
+index 1d3c2ab..7a53d84 100644
+--- a/main.go
++++ b/main.go
+@@ -3,3 +3,4 @@ import "fmt"
+ func main() {
+-	fmt.Println("old")
++	fmt.Println("new")
++	fmt.Println("--- not a header")
+ }
+\ No newline at end of file
+diff --git a/docs/old name.md b/docs/new name.md
+similarity index 100%
+rename from docs/old name.md
+rename to docs/new name.md
+diff --git a/gone.txt b/gone.txt
+deleted file mode 100644
+index 257cc56..0000000
+--- a/gone.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-bye
+diff --git a/logo.png b/logo.png
+new file mode 100644
+index 0000000..1a2b3c4
+Binary files /dev/null and b/logo.png differ
+--
+2.45.0
+`
+
+func TestParsePatch(t *testing.T) {
+	p, err := gerrit.ParsePatch(testPatch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if p.Commit != "674ac754f91e64a0efb8087e59a176484bd534d1" {
+		t.Errorf("Commit = %q", p.Commit)
+	}
+	if p.Author.Name != "Jürgen Doe" || p.Author.Email != "jdoe@example.com" || p.Author.TZ != 120 {
+		t.Errorf("Author = %+v", p.Author)
+	}
+	if want := time.Date(2015, 6, 2, 13, 36, 7, 0, time.UTC); !p.Author.Date.Equal(want) {
+		t.Errorf("Date = %v, want %v", p.Author.Date, want)
+	}
+	if want := "Use an explicit flag for the new behaviour"; p.Subject != want {
+		t.Errorf("Subject = %q, want %q", p.Subject, want)
+	}
+	if want := "Use an explicit flag for the new behaviour\n\nThe old heuristic was wrong.\n\nChange-Id: I8473b95934b5732ac55d26311a706c9c2bde9940\n"; p.Message != want {
+		t.Errorf("Message:\ngot:  %q\nwant: %q", p.Message, want)
+	}
+
+	want := []gerrit.PatchFile{
+		{
+			OldPath: "main.go", NewPath: "main.go", ChangeType: "MODIFIED",
+			Hunks: []gerrit.PatchHunk{{
+				OldStart: 3, OldLines: 3, NewStart: 3, NewLines: 4, Section: `import "fmt"`,
+				Lines: []string{
+					" func main() {",
+					"-\tfmt.Println(\"old\")",
+					"+\tfmt.Println(\"new\")",
+					"+\tfmt.Println(\"--- not a header\")",
+					" }",
+					`\ No newline at end of file`,
+				},
+			}},
+		},
+		{OldPath: "docs/old name.md", NewPath: "docs/new name.md", ChangeType: "RENAMED"},
+		{
+			OldPath: "gone.txt", ChangeType: "DELETED",
+			Hunks: []gerrit.PatchHunk{{OldStart: 1, OldLines: 1, NewStart: 0, NewLines: 0, Lines: []string{"-bye"}}},
+		},
+		{NewPath: "logo.png", ChangeType: "ADDED", Binary: true},
+	}
+	if !reflect.DeepEqual(p.Files, want) {
+		t.Errorf("Files:\ngot:  %+v\nwant: %+v", p.Files, want)
+	}
+}
+
+func TestParsePatch_Invalid(t *testing.T) {
+	for _, text := range []string{
+		"",
+		"diff --git a/x b/x\n",
+		"From 674ac754f91e64a0efb8087e59a176484bd534d1 Mon Sep 17 00:00:00 2001",
+	} {
+		if _, err := gerrit.ParsePatch(text); err != gerrit.ErrInvalidPatch {
+			t.Errorf("ParsePatch(%q) error = %v, want %v", text, err, gerrit.ErrInvalidPatch)
+		}
+	}
+}
+
+func TestChangesService_GetParsedPatch(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/changes/123/revisions/current/patch", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if r.URL.Query().Get("zip") == "" {
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte(base64.StdEncoding.EncodeToString([]byte(testPatch)))) // nolint: errcheck
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/zip")
+		zw := zip.NewWriter(w)
+		f, err := zw.Create("674ac754.diff")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		f.Write([]byte(testPatch)) // nolint: errcheck
+		if err := zw.Close(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	for _, opt := range []*gerrit.PatchOptions{nil, {Zip: true}} {
+		var buf bytes.Buffer
+		if _, err := testClient.Changes.WritePatch(context.Background(), "123", "current", opt, &buf); err != nil {
+			t.Fatalf("WritePatch(%+v): %v", opt, err)
+		}
+		if buf.String() != testPatch {
+			t.Errorf("WritePatch(%+v) wrote %q", opt, buf.String())
+		}
+
+		p, _, err := testClient.Changes.GetParsedPatch(context.Background(), "123", "current", opt)
+		if err != nil {
+			t.Fatalf("GetParsedPatch(%+v): %v", opt, err)
+		}
+		if len(p.Files) != 4 {
+			t.Errorf("GetParsedPatch(%+v) returned %d files", opt, len(p.Files))
+		}
+	}
+}
@@ -30,9 +30,10 @@ type AddReviewerResult struct {
 //
 // Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#delete-vote-input
 type DeleteVoteInput struct {
-	Label         string                `json:"label,omitempty"`
-	Notify        string                `json:"notify,omitempty"`
-	NotifyDetails map[string]NotifyInfo `json:"notify_details"`
+	Label                            string                `json:"label,omitempty"`
+	Notify                           string                `json:"notify,omitempty"`
+	NotifyDetails                    map[string]NotifyInfo `json:"notify_details,omitempty"`
+	IgnoreAutomaticAttentionSetRules bool                  `json:"ignore_automatic_attention_set_rules,omitempty"`
 }
 
 // ListReviewers lists the reviewers of a change.
@@ -162,3 +163,57 @@ func (s *ChangesService) DeleteVote(ctx context.Context, changeID string, accoun
 	u := fmt.Sprintf("changes/%s/reviewers/%s/votes/%s", changeID, accountID, label)
 	return s.client.DeleteRequest(ctx, u, input)
 }
+
+// ListRevisionReviewers lists the reviewers of a revision.
+// Please note that only the current revision is supported.
+//
+// As result a list of ReviewerInfo entries is returned.
+// The approvals of each reviewer are the votes cast on this revision.
+//
+// Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#list-revision-reviewers
+func (s *ChangesService) ListRevisionReviewers(ctx context.Context, changeID, revisionID string) ([]ReviewerInfo, *Response, error) {
+	u := fmt.Sprintf("changes/%s/revisions/%s/reviewers/", changeID, revisionID)
+
+	req, err := s.client.NewRequest(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var v []ReviewerInfo
+	resp, err := s.client.Do(req, &v)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return v, resp, err
+}
+
+// ListRevisionVotes lists the votes for a specific reviewer of the revision.
+// Please note that only the current revision is supported.
+//
+// Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#list-revision-votes
+func (s *ChangesService) ListRevisionVotes(ctx context.Context, changeID, revisionID, accountID string) (map[string]int, *Response, error) {
+	u := fmt.Sprintf("changes/%s/revisions/%s/reviewers/%s/votes/", changeID, revisionID, accountID)
+	req, err := s.client.NewRequest(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var v map[string]int
+	resp, err := s.client.Do(req, &v)
+	if err != nil {
+		return nil, resp, err
+	}
+	return v, resp, err
+}
+
+// DeleteRevisionVote deletes a single vote from a revision.
+// The deletion will be possible only if the revision is the current revision.
+// By using this endpoint you can prevent deleting the vote (with same label)
+// from a newer patch set by mistake.
+//
+// Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#delete-vote
+func (s *ChangesService) DeleteRevisionVote(ctx context.Context, changeID, revisionID, accountID, label string, input *DeleteVoteInput) (*Response, error) {
+	u := fmt.Sprintf("changes/%s/revisions/%s/reviewers/%s/votes/%s", changeID, revisionID, accountID, label)
+	return s.client.DeleteRequest(ctx, u, input)
+}
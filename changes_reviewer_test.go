@@ -202,6 +202,89 @@ func TestChangesService_DeleteVote(t *testing.T) {
 	}
 }
 
+func TestChangesService_ListRevisionReviewers(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expected := "/changes/123/revisions/2/reviewers/"
+		if r.URL.Path != expected {
+			t.Errorf("%s != %s", r.URL.Path, expected)
+		}
+
+		_, err := fmt.Fprint(w, `[{"_account_id": 1, "approvals": {"Verified": "+1", "Code-Review": "+2"}}]`)
+		if err != nil {
+			t.Error(err)
+		}
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	client := newClient(ctx, t, ts)
+	data, _, err := client.Changes.ListRevisionReviewers(ctx, "123", "2")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if len(data) != 1 {
+		t.Fatal("Length of data !=1 ")
+	}
+	if data[0].Approvals["Code-Review"] != "+2" {
+		t.Error("Code-Review != +2")
+	}
+}
+
+func TestChangesService_ListRevisionVotes(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expected := "/changes/123/revisions/2/reviewers/1/votes/"
+		if r.URL.Path != expected {
+			t.Errorf("%s != %s", r.URL.Path, expected)
+		}
+		_, err := fmt.Fprint(w, `{"Code-Review": -1, "Verified": 1}`)
+		if err != nil {
+			t.Error(err)
+		}
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	client := newClient(ctx, t, ts)
+	votes, _, err := client.Changes.ListRevisionVotes(ctx, "123", "2", "1")
+	if err != nil {
+		t.Error(err)
+	}
+	if votes["Code-Review"] != -1 {
+		t.Error("Code-Review != -1")
+	}
+}
+
+func TestChangesService_DeleteRevisionVote(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expected := "/changes/123/revisions/2/reviewers/1/votes/Code-Review"
+		if r.URL.Path != expected {
+			t.Errorf("%s != %s", r.URL.Path, expected)
+		}
+
+		if r.Method != "DELETE" {
+			t.Error("Method != DELETE")
+		}
+
+		var input gerrit.DeleteVoteInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			t.Error(err)
+		}
+		if input.Notify != "NONE" {
+			t.Errorf("Notify = %q, want NONE", input.Notify)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	client := newClient(ctx, t, ts)
+	_, err := client.Changes.DeleteRevisionVote(ctx, "123", "2", "1", "Code-Review", &gerrit.DeleteVoteInput{Notify: "NONE"})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
 func TestChangesService_AddReviewer_WithState(t *testing.T) {
 	testCases := []struct {
 		name          string
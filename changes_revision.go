@@ -314,6 +314,30 @@ func (s *ChangesService) ListRevisionComments(ctx context.Context, changeID, rev
 	return s.getCommentInfoMapSliceResponse(ctx, u)
 }
 
+// ListPortedComments lists the published comments of all unresolved comment threads
+// of the change which were left on previous patch sets, ported to the given revision.
+// The positions (line, range) of the comments refer to the given revision,
+// comments whose position can't be determined are ported to the file level.
+// Returns a map of file paths to lists of CommentInfo entries.
+//
+// This endpoint is available since Gerrit 3.4. See ListPortedCommentsWithFallback for older servers.
+//
+// Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#list-ported-comments
+func (s *ChangesService) ListPortedComments(ctx context.Context, changeID, revisionID string) (*map[string][]CommentInfo, *Response, error) {
+	u := fmt.Sprintf("changes/%s/revisions/%s/ported_comments", changeID, revisionID)
+	return s.getCommentInfoMapSliceResponse(ctx, u)
+}
+
+// ListPortedDrafts lists the draft comments of the calling user which were left on
+// previous patch sets, ported to the given revision.
+// Returns a map of file paths to lists of CommentInfo entries.
+//
+// Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#list-ported-drafts
+func (s *ChangesService) ListPortedDrafts(ctx context.Context, changeID, revisionID string) (*map[string][]CommentInfo, *Response, error) {
+	u := fmt.Sprintf("changes/%s/revisions/%s/ported_drafts", changeID, revisionID)
+	return s.getCommentInfoMapSliceResponse(ctx, u)
+}
+
 // ListFiles lists the files that were modified, added or deleted in a revision.
 // As result a map is returned that maps the file path to a list of FileInfo entries.
 // The entries in the map are sorted by file path.
@@ -644,6 +668,53 @@ func (s *ChangesService) CherryPickRevision(ctx context.Context, changeID, revis
 	return v, resp, err
 }
 
+// DescriptionInput entity contains information for setting a description.
+type DescriptionInput struct {
+	Description string `json:"description"`
+}
+
+// GetRevisionDescription retrieves the description of a patch set.
+//
+// If the patch set does not have a description an empty string is returned.
+//
+// Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#get-description
+func (s *ChangesService) GetRevisionDescription(ctx context.Context, changeID, revisionID string) (string, *Response, error) {
+	u := fmt.Sprintf("changes/%s/revisions/%s/description", changeID, revisionID)
+	return getStringResponseWithoutOptions(ctx, s.client, u)
+}
+
+// SetRevisionDescription sets the description of a patch set.
+// The new description must be provided in the request body inside a DescriptionInput entity.
+//
+// As response the new description is returned.
+//
+// Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#set-description
+func (s *ChangesService) SetRevisionDescription(ctx context.Context, changeID, revisionID string, input *DescriptionInput) (*string, *Response, error) {
+	u := fmt.Sprintf("changes/%s/revisions/%s/description", changeID, revisionID)
+
+	req, err := s.client.NewRequest(ctx, "PUT", u, input)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	v := new(string)
+	resp, err := s.client.Do(req, v)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return v, resp, err
+}
+
+// DeleteRevisionDescription deletes the description of a patch set.
+// Gerrit has no separate endpoint for this, the description is removed by setting an empty one.
+//
+// Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#set-description
+func (s *ChangesService) DeleteRevisionDescription(ctx context.Context, changeID, revisionID string) (*Response, error) {
+	u := fmt.Sprintf("changes/%s/revisions/%s/description", changeID, revisionID)
+	return s.client.Call(ctx, "PUT", u, &DescriptionInput{}, nil)
+}
+
 /*
 TODO: Missing Revision Endpoints
 	Rebase Revision
@@ -2,6 +2,7 @@ package gerrit_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -88,3 +89,107 @@ func TestChangesService_ListFilesReviewed(t *testing.T) {
 		t.Errorf("client.Changes.ListFilesReviewed:\ngot:  %q\nwant: %q", got, want)
 	}
 }
+
+func TestChangesService_ListPortedComments(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.String(), "/changes/123/revisions/3/ported_comments"; got != want {
+			t.Errorf("request URL:\ngot:  %q\nwant: %q", got, want)
+		}
+		_, err := fmt.Fprint(w, `{
+		  "foo.go": [
+		    {"id": "c1", "patch_set": 1, "line": 12, "message": "nit", "unresolved": true}
+		  ]
+		}`)
+		if err != nil {
+			t.Error(err)
+		}
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	client := newClient(ctx, t, ts)
+	got, _, err := client.Changes.ListPortedComments(ctx, "123", "3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := (*got)["foo.go"]; len(c) != 1 || c[0].Line != 12 || c[0].PatchSet != 1 {
+		t.Errorf("Unexpected ported comments %+v", *got)
+	}
+}
+
+func TestChangesService_ListPortedDrafts(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.String(), "/changes/123/revisions/current/ported_drafts"; got != want {
+			t.Errorf("request URL:\ngot:  %q\nwant: %q", got, want)
+		}
+		_, err := fmt.Fprint(w, `{}`)
+		if err != nil {
+			t.Error(err)
+		}
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	client := newClient(ctx, t, ts)
+	got, _, err := client.Changes.ListPortedDrafts(ctx, "123", "current")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*got) != 0 {
+		t.Errorf("Unexpected ported drafts %+v", *got)
+	}
+}
+
+func TestChangesService_RevisionDescription(t *testing.T) {
+	description := "Rebased on top of the fix"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.Path, "/changes/123/revisions/2/description"; got != want {
+			t.Errorf("request path:\ngot:  %q\nwant: %q", got, want)
+		}
+		switch r.Method {
+		case "GET":
+		case "PUT":
+			var input gerrit.DescriptionInput
+			if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+				t.Error(err)
+			}
+			description = input.Description
+			if description == "" {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		default:
+			t.Errorf("unexpected method %q", r.Method)
+		}
+		if err := json.NewEncoder(w).Encode(description); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	client := newClient(ctx, t, ts)
+
+	got, _, err := client.Changes.GetRevisionDescription(ctx, "123", "2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "Rebased on top of the fix" {
+		t.Errorf("GetRevisionDescription() = %q", got)
+	}
+
+	set, _, err := client.Changes.SetRevisionDescription(ctx, "123", "2", &gerrit.DescriptionInput{Description: "Addressed comments"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *set != "Addressed comments" {
+		t.Errorf("SetRevisionDescription() = %q", *set)
+	}
+
+	if _, err := client.Changes.DeleteRevisionDescription(ctx, "123", "2"); err != nil {
+		t.Fatal(err)
+	}
+	if description != "" {
+		t.Errorf("description after DeleteRevisionDescription() = %q", description)
+	}
+}
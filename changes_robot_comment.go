@@ -0,0 +1,153 @@
+package gerrit
+
+import (
+	"context"
+	"fmt"
+)
+
+// ApplyProvidedFixInput entity contains information for applying fixes, provided in the request body, to a revision.
+//
+// Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#apply-provided-fix
+type ApplyProvidedFixInput struct {
+	// The fix(es) to be applied as a list of FixReplacementInfo entities.
+	FixReplacementInfos []FixReplacementInfo `json:"fix_replacement_infos"`
+	// The patch set the fix was created for.
+	// If the revision the fix is applied to is newer, the fix is rebased onto it.
+	OriginalPatchsetForFix int `json:"original_patchset_for_fix,omitempty"`
+}
+
+// ListChangeRobotComments lists the robot comments of all revisions of the change.
+// Return a map that maps the file path to a list of RobotCommentInfo entries.
+// The entries in the map are sorted by file path.
+//
+// Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#list-change-robot-comments
+func (s *ChangesService) ListChangeRobotComments(ctx context.Context, changeID string) (map[string][]RobotCommentInfo, *Response, error) {
+	u := fmt.Sprintf("changes/%s/robotcomments", changeID)
+	return s.getRobotCommentInfoMapResponse(ctx, u)
+}
+
+// ListRevisionRobotComments lists the robot comments of a revision.
+// As result a map is returned that maps the file path to a list of RobotCommentInfo entries.
+// The entries in the map are sorted by file path.
+//
+// Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#list-robot-comments
+func (s *ChangesService) ListRevisionRobotComments(ctx context.Context, changeID, revisionID string) (map[string][]RobotCommentInfo, *Response, error) {
+	u := fmt.Sprintf("changes/%s/revisions/%s/robotcomments/", changeID, revisionID)
+	return s.getRobotCommentInfoMapResponse(ctx, u)
+}
+
+// getRobotCommentInfoMapResponse retrieved a map of RobotCommentInfo Response for a GET request
+func (s *ChangesService) getRobotCommentInfoMapResponse(ctx context.Context, u string) (map[string][]RobotCommentInfo, *Response, error) {
+	req, err := s.client.NewRequest(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var v map[string][]RobotCommentInfo
+	resp, err := s.client.Do(req, &v)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return v, resp, err
+}
+
+// GetRobotComment retrieves a robot comment of a revision.
+//
+// Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#get-robot-comment
+func (s *ChangesService) GetRobotComment(ctx context.Context, changeID, revisionID, commentID string) (*RobotCommentInfo, *Response, error) {
+	u := fmt.Sprintf("changes/%s/revisions/%s/robotcomments/%s", changeID, revisionID, commentID)
+
+	req, err := s.client.NewRequest(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	v := new(RobotCommentInfo)
+	resp, err := s.client.Do(req, v)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return v, resp, err
+}
+
+// PreviewFix gets the diffs of all files for the fix provided in the request body,
+// without creating a change edit.
+//
+// As response a map of file paths to DiffInfo entries is returned.
+//
+// Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#preview-provided-fix
+func (s *ChangesService) PreviewFix(ctx context.Context, changeID, revisionID string, input *ApplyProvidedFixInput) (map[string]DiffInfo, *Response, error) {
+	u := fmt.Sprintf("changes/%s/revisions/%s/fix:preview", changeID, revisionID)
+	return s.getFixPreviewResponse(ctx, "POST", u, input)
+}
+
+// PreviewStoredFix gets the diffs of all files for a certain suggested fix of a robot comment,
+// without creating a change edit.
+//
+// As response a map of file paths to DiffInfo entries is returned.
+//
+// Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#preview-stored-fix
+func (s *ChangesService) PreviewStoredFix(ctx context.Context, changeID, revisionID, fixID string) (map[string]DiffInfo, *Response, error) {
+	u := fmt.Sprintf("changes/%s/revisions/%s/fixes/%s/preview", changeID, revisionID, fixID)
+	return s.getFixPreviewResponse(ctx, "GET", u, nil)
+}
+
+// getFixPreviewResponse retrieved a map of DiffInfo Response for a fix preview request
+func (s *ChangesService) getFixPreviewResponse(ctx context.Context, method, u string, input interface{}) (map[string]DiffInfo, *Response, error) {
+	req, err := s.client.NewRequest(ctx, method, u, input)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var v map[string]DiffInfo
+	resp, err := s.client.Do(req, &v)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return v, resp, err
+}
+
+// ApplyFix applies the fix provided in the request body to the revision.
+// The fix is applied to a change edit, which is created if it doesn't exist yet.
+// The change edit has to be based on the given revision.
+// Publish the change edit with PublishChangeEdit to create a new patch set.
+//
+// As response an EditInfo entity is returned that describes the change edit.
+//
+// Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#apply-provided-fix
+func (s *ChangesService) ApplyFix(ctx context.Context, changeID, revisionID string, input *ApplyProvidedFixInput) (*EditInfo, *Response, error) {
+	u := fmt.Sprintf("changes/%s/revisions/%s/fix:apply", changeID, revisionID)
+	return s.applyFix(ctx, u, input)
+}
+
+// ApplyStoredFix applies a suggested fix of a robot comment to the revision.
+// The fix is applied to a change edit, which is created if it doesn't exist yet.
+// The change edit has to be based on the given revision.
+// Publish the change edit with PublishChangeEdit to create a new patch set.
+//
+// As response an EditInfo entity is returned that describes the change edit.
+//
+// Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#apply-stored-fix
+func (s *ChangesService) ApplyStoredFix(ctx context.Context, changeID, revisionID, fixID string) (*EditInfo, *Response, error) {
+	u := fmt.Sprintf("changes/%s/revisions/%s/fixes/%s/apply", changeID, revisionID, fixID)
+	return s.applyFix(ctx, u, nil)
+}
+
+// applyFix is an internal function to consolidate code used by ApplyFix and ApplyStoredFix.
+func (s *ChangesService) applyFix(ctx context.Context, u string, input interface{}) (*EditInfo, *Response, error) {
+	req, err := s.client.NewRequest(ctx, "POST", u, input)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	v := new(EditInfo)
+	resp, err := s.client.Do(req, v)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return v, resp, err
+}
@@ -0,0 +1,199 @@
+package gerrit_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andygrunwald/go-gerrit"
+)
+
+const robotCommentsResponse = `)]}'
+{
+  "main.c": [
+    {
+      "robot_id": "importantChecker",
+      "robot_run_id": "1xe1a8e",
+      "fix_suggestions": [
+        {
+          "fix_id": "c3302a6f_1578ee9e",
+          "description": "Add a semicolon",
+          "replacements": [
+            {
+              "path": "main.c",
+              "range": {"start_line": 23, "start_character": 10, "end_line": 23, "end_character": 10},
+              "replacement": ";"
+            },
+            {
+              "path": "main.c",
+              "range": {"start_line": 24, "start_character": 0, "end_line": 25, "end_character": 0},
+              "replacement": ""
+            }
+          ]
+        }
+      ],
+      "patch_set": 1,
+      "id": "TvcXrmjM",
+      "line": 23,
+      "message": "Missing semicolon",
+      "updated": "2013-02-26 15:40:43.986000000"
+    }
+  ]
+}`
+
+func TestChangesService_ListChangeRobotComments(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, "/changes/123/robotcomments")
+		if _, err := fmt.Fprint(w, robotCommentsResponse); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	client := newClient(ctx, t, ts)
+	got, _, err := client.Changes.ListChangeRobotComments(ctx, "123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	comments := got["main.c"]
+	if len(comments) != 1 {
+		t.Fatalf("Unexpected comments %+v", got)
+	}
+	c := comments[0]
+	if c.RobotID != "importantChecker" || c.ID != "TvcXrmjM" || c.Line != 23 {
+		t.Errorf("Unexpected comment %+v", c)
+	}
+	if len(c.FixSuggestions) != 1 || len(c.FixSuggestions[0].Replacements) != 2 {
+		t.Fatalf("Unexpected fix suggestions %+v", c.FixSuggestions)
+	}
+	if r := c.FixSuggestions[0].Replacements[0]; r.Replacement != ";" || r.Range.StartCharacter != 10 {
+		t.Errorf("Unexpected replacement %+v", r)
+	}
+}
+
+func TestChangesService_ListRevisionRobotComments(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, "/changes/123/revisions/1/robotcomments/")
+		if _, err := fmt.Fprint(w, robotCommentsResponse); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	client := newClient(ctx, t, ts)
+	got, _, err := client.Changes.ListRevisionRobotComments(ctx, "123", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got["main.c"]) != 1 {
+		t.Errorf("Unexpected comments %+v", got)
+	}
+}
+
+func TestChangesService_GetRobotComment(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, "/changes/123/revisions/1/robotcomments/TvcXrmjM")
+		_, err := fmt.Fprint(w, `)]}'
+{"robot_id": "importantChecker", "robot_run_id": "1xe1a8e", "id": "TvcXrmjM", "path": "main.c", "line": 23}`)
+		if err != nil {
+			t.Error(err)
+		}
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	client := newClient(ctx, t, ts)
+	got, _, err := client.Changes.GetRobotComment(ctx, "123", "1", "TvcXrmjM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.RobotID != "importantChecker" || got.Path != "main.c" {
+		t.Errorf("Unexpected comment %+v", got)
+	}
+}
+
+func TestChangesService_PreviewStoredFix(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, "/changes/123/revisions/1/fixes/c3302a6f_1578ee9e/preview")
+		_, err := fmt.Fprint(w, `)]}'
+{"main.c": {"change_type": "MODIFIED", "content": [{"a": ["int x = 1"], "b": ["int x = 1;"]}]}}`)
+		if err != nil {
+			t.Error(err)
+		}
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	client := newClient(ctx, t, ts)
+	got, _, err := client.Changes.PreviewStoredFix(ctx, "123", "1", "c3302a6f_1578ee9e")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d, ok := got["main.c"]; !ok || d.ChangeType != "MODIFIED" || len(d.Content) != 1 {
+		t.Errorf("Unexpected preview %+v", got)
+	}
+}
+
+func TestChangesService_ApplyFix(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testRequestURL(t, r, "/changes/123/revisions/current/fix:apply")
+
+		var input gerrit.ApplyProvidedFixInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			t.Error(err)
+		}
+		if len(input.FixReplacementInfos) != 1 || input.FixReplacementInfos[0].Path != "main.c" {
+			t.Errorf("Unexpected input %+v", input)
+		}
+
+		_, err := fmt.Fprint(w, `)]}'
+{"commit": {"commit": "6f5e5bbd0a6a24f4e2bde1ef5cc1e1c1f1d6a5a3", "subject": "Fix"}, "base_patch_set_number": 1}`)
+		if err != nil {
+			t.Error(err)
+		}
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	client := newClient(ctx, t, ts)
+	got, _, err := client.Changes.ApplyFix(ctx, "123", "current", &gerrit.ApplyProvidedFixInput{
+		FixReplacementInfos: []gerrit.FixReplacementInfo{
+			{Path: "main.c", Range: gerrit.CommentRange{StartLine: 23, StartCharacter: 10, EndLine: 23, EndCharacter: 10}, Replacement: ";"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Commit.Commit != "6f5e5bbd0a6a24f4e2bde1ef5cc1e1c1f1d6a5a3" {
+		t.Errorf("Unexpected edit %+v", got)
+	}
+}
+
+func TestChangesService_ApplyStoredFix(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testRequestURL(t, r, "/changes/123/revisions/1/fixes/c3302a6f_1578ee9e/apply")
+		_, err := fmt.Fprint(w, `)]}'
+{"commit": {"commit": "6f5e5bbd0a6a24f4e2bde1ef5cc1e1c1f1d6a5a3", "subject": "Fix"}}`)
+		if err != nil {
+			t.Error(err)
+		}
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	client := newClient(ctx, t, ts)
+	if _, _, err := client.Changes.ApplyStoredFix(ctx, "123", "1", "c3302a6f_1578ee9e"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,305 @@
+package gerrit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// StackMember is a change within a Stack.
+type StackMember struct {
+	RelatedChangeAndCommitInfo
+
+	// Parents are the indices of the parent members within Stack.Members.
+	Parents []int
+	// Children are the indices of the child members within Stack.Members.
+	Children []int
+}
+
+// Outdated reports whether the patch set of the member within the stack
+// is not the current patch set of its change.
+func (m *StackMember) Outdated() bool {
+	return m.RevisionNumber < m.CurrentRevisionNumber
+}
+
+// open reports whether the change of the member can still be submitted or rebased.
+func (m *StackMember) open() bool {
+	return m.Status == "" || m.Status == "NEW"
+}
+
+// Stack is a graph of related changes ("relation chain"), built from the result of GetRelatedChanges.
+// The edges are the parent/child relations of the commits of the members.
+type Stack struct {
+	// Members are the changes of the stack in topological order, parents first.
+	Members []StackMember
+
+	byCommit map[string]int
+	byChange map[int]int
+}
+
+// NewStack builds a Stack from the related changes of a revision.
+func NewStack(related *RelatedChangesInfo) *Stack {
+	// GetRelatedChanges returns the changes with the descendants first.
+	// Reverse them, so that the order is stable and parents come first as far as possible.
+	infos := make([]RelatedChangeAndCommitInfo, 0, len(related.Changes))
+	for i := len(related.Changes) - 1; i >= 0; i-- {
+		infos = append(infos, related.Changes[i])
+	}
+
+	index := make(map[string]int, len(infos))
+	for i, info := range infos {
+		index[info.Commit.Commit] = i
+	}
+	parents := make([][]int, len(infos))
+	inDegree := make([]int, len(infos))
+	for i, info := range infos {
+		for _, p := range info.Commit.Parents {
+			if j, ok := index[p.Commit]; ok {
+				parents[i] = append(parents[i], j)
+				inDegree[i]++
+			}
+		}
+	}
+
+	// Kahn's algorithm, always picking the first ready member to keep the order stable.
+	order := make([]int, 0, len(infos))
+	done := make([]bool, len(infos))
+	for len(order) < len(infos) {
+		next := -1
+		for i := range infos {
+			if !done[i] && inDegree[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			// A cycle can't happen in git history, but don't loop forever on bad input.
+			for i := range infos {
+				if !done[i] {
+					next = i
+					break
+				}
+			}
+		}
+		done[next] = true
+		order = append(order, next)
+		for i := range infos {
+			for _, p := range parents[i] {
+				if p == next {
+					inDegree[i]--
+				}
+			}
+		}
+	}
+
+	s := &Stack{
+		byCommit: make(map[string]int, len(infos)),
+		byChange: make(map[int]int, len(infos)),
+	}
+	position := make([]int, len(infos))
+	for pos, i := range order {
+		position[i] = pos
+		s.Members = append(s.Members, StackMember{RelatedChangeAndCommitInfo: infos[i]})
+		s.byCommit[infos[i].Commit.Commit] = pos
+		s.byChange[infos[i].ChangeNumber] = pos
+	}
+	for i := range infos {
+		for _, p := range parents[i] {
+			child, parent := position[i], position[p]
+			s.Members[child].Parents = append(s.Members[child].Parents, parent)
+			s.Members[parent].Children = append(s.Members[parent].Children, child)
+		}
+	}
+	return s
+}
+
+// Member returns the member of the stack with the given change number.
+func (s *Stack) Member(changeNumber int) (*StackMember, bool) {
+	i, ok := s.byChange[changeNumber]
+	if !ok {
+		return nil, false
+	}
+	return &s.Members[i], true
+}
+
+// NeedsRebase returns the open members that are based on an outdated patch set of a parent member.
+func (s *Stack) NeedsRebase() []StackMember {
+	var result []StackMember
+	for _, m := range s.Members {
+		if !m.open() {
+			continue
+		}
+		for _, p := range m.Parents {
+			if s.Members[p].Outdated() {
+				result = append(result, m)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// SubmitOrder returns the open members in the order they have to be submitted, parents first.
+func (s *Stack) SubmitOrder() []StackMember {
+	var result []StackMember
+	for _, m := range s.Members {
+		if m.open() {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+// ancestors returns the indices of the member with the given change number and all its ancestors,
+// in topological order.
+func (s *Stack) ancestors(changeNumber int) []int {
+	start, ok := s.byChange[changeNumber]
+	if !ok {
+		return nil
+	}
+	seen := map[int]bool{start: true}
+	queue := []int{start}
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		for _, p := range s.Members[i].Parents {
+			if !seen[p] {
+				seen[p] = true
+				queue = append(queue, p)
+			}
+		}
+	}
+	var result []int
+	for i := range s.Members {
+		if seen[i] {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+// StackResult is the outcome of an operation on a single change of a stack.
+type StackResult struct {
+	ChangeNumber int
+	// Change is the updated change, if the operation succeeded.
+	Change *ChangeInfo
+	// Err is the error of the operation, if it failed.
+	Err error
+	// Skipped is true if the operation was not attempted because an earlier operation failed.
+	Skipped bool
+}
+
+// getStack retrieves the stack of the current revision of the change.
+func (s *ChangesService) getStack(ctx context.Context, changeID string) (*Stack, *ChangeInfo, error) {
+	change, _, err := s.GetChange(ctx, changeID, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	related, _, err := s.GetRelatedChanges(ctx, changeID, "current")
+	if err != nil {
+		return nil, nil, err
+	}
+	stack := NewStack(related)
+	if _, ok := stack.Member(change.Number); !ok {
+		// The change is not part of a relation chain.
+		stack = NewStack(&RelatedChangesInfo{Changes: []RelatedChangeAndCommitInfo{{
+			ChangeID:     change.ChangeID,
+			ChangeNumber: change.Number,
+			Status:       change.Status,
+		}}})
+	}
+	return stack, change, nil
+}
+
+// SubmitStack submits the change and all its open ancestors in its relation chain, parents first.
+// Descendants of the change are not submitted.
+//
+// Submitting stops at the first failure; the remaining changes are reported as skipped.
+// The returned error is the error of the first failure.
+func (s *ChangesService) SubmitStack(ctx context.Context, changeID string, input *SubmitInput) ([]StackResult, error) {
+	stack, change, err := s.getStack(ctx, changeID)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []StackResult
+	var firstErr error
+	for _, i := range stack.ancestors(change.Number) {
+		m := stack.Members[i]
+		if !m.open() {
+			continue
+		}
+		r := StackResult{ChangeNumber: m.ChangeNumber}
+		if firstErr != nil {
+			r.Skipped = true
+		} else {
+			r.Change, _, r.Err = s.SubmitChange(ctx, strconv.Itoa(m.ChangeNumber), input)
+			if r.Err != nil {
+				firstErr = fmt.Errorf("submitting change %d: %v", m.ChangeNumber, r.Err)
+			}
+		}
+		results = append(results, r)
+	}
+	return results, firstErr
+}
+
+// RebaseStack rebases the open changes of the relation chain of the change that are based on
+// an outdated patch set of their parent, parents first.
+// Each change is rebased onto the current patch set of its parent change,
+// so rebasing a change causes its descendants to be rebased as well.
+// input may be used to pass further rebase options; its Base field is overwritten.
+//
+// Rebasing stops at the first failure; the remaining changes are reported as skipped.
+// The returned error is the error of the first failure.
+func (s *ChangesService) RebaseStack(ctx context.Context, changeID string, input *RebaseInput) ([]StackResult, error) {
+	stack, _, err := s.getStack(ctx, changeID)
+	if err != nil {
+		return nil, err
+	}
+
+	moved := map[int]bool{}
+	for i, m := range stack.Members {
+		if m.Outdated() {
+			moved[i] = true
+		}
+	}
+
+	var results []StackResult
+	var firstErr error
+	for i, m := range stack.Members {
+		if !m.open() {
+			continue
+		}
+		parent := -1
+		for _, p := range m.Parents {
+			if moved[p] {
+				parent = p
+				break
+			}
+		}
+		if parent < 0 {
+			continue
+		}
+
+		r := StackResult{ChangeNumber: m.ChangeNumber}
+		if firstErr != nil {
+			r.Skipped = true
+			results = append(results, r)
+			continue
+		}
+
+		in := RebaseInput{}
+		if input != nil {
+			in = *input
+		}
+		in.Base = strconv.Itoa(stack.Members[parent].ChangeNumber)
+		r.Change, _, r.Err = s.RebaseChange(ctx, strconv.Itoa(m.ChangeNumber), &in)
+		if r.Err != nil {
+			firstErr = fmt.Errorf("rebasing change %d: %v", m.ChangeNumber, r.Err)
+		}
+		// Mark the change as moved even if rebasing failed, so that its descendants are reported as skipped.
+		moved[i] = true
+		results = append(results, r)
+	}
+	return results, firstErr
+}
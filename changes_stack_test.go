@@ -0,0 +1,133 @@
+package gerrit_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/andygrunwald/go-gerrit"
+)
+
+// relatedChangesJSON describes the stack 101 <- 102 <- 103 on top of the merged change 100,
+// where 102 is based on the outdated patch set 1 of 101.
+const relatedChangesJSON = `{
+  "changes": [
+    {"change_id": "I3", "commit": {"commit": "c3", "parents": [{"commit": "c2"}]}, "_change_number": 103, "_revision_number": 1, "_current_revision_number": 1, "status": "NEW"},
+    {"change_id": "I2", "commit": {"commit": "c2", "parents": [{"commit": "c1"}]}, "_change_number": 102, "_revision_number": 1, "_current_revision_number": 1, "status": "NEW"},
+    {"change_id": "I1", "commit": {"commit": "c1", "parents": [{"commit": "c0"}]}, "_change_number": 101, "_revision_number": 1, "_current_revision_number": 2, "status": "NEW"},
+    {"change_id": "I0", "commit": {"commit": "c0", "parents": [{"commit": "base"}]}, "_change_number": 100, "_revision_number": 1, "_current_revision_number": 1, "status": "MERGED"}
+  ]
+}`
+
+func TestNewStack(t *testing.T) {
+	var related gerrit.RelatedChangesInfo
+	if err := json.Unmarshal([]byte(relatedChangesJSON), &related); err != nil {
+		t.Fatal(err)
+	}
+	stack := gerrit.NewStack(&related)
+
+	var order []int
+	for _, m := range stack.Members {
+		order = append(order, m.ChangeNumber)
+	}
+	if want := []int{100, 101, 102, 103}; !reflect.DeepEqual(order, want) {
+		t.Errorf("Members order = %v, want %v", order, want)
+	}
+
+	m, ok := stack.Member(102)
+	if !ok || !reflect.DeepEqual(m.Parents, []int{1}) || !reflect.DeepEqual(m.Children, []int{3}) {
+		t.Errorf("Unexpected member 102: %+v", m)
+	}
+	if m, _ := stack.Member(101); !m.Outdated() {
+		t.Error("Member 101 should be outdated")
+	}
+
+	if rebase := stack.NeedsRebase(); len(rebase) != 1 || rebase[0].ChangeNumber != 102 {
+		t.Errorf("NeedsRebase() = %+v", rebase)
+	}
+
+	var submit []int
+	for _, m := range stack.SubmitOrder() {
+		submit = append(submit, m.ChangeNumber)
+	}
+	if want := []int{101, 102, 103}; !reflect.DeepEqual(submit, want) {
+		t.Errorf("SubmitOrder() = %v, want %v", submit, want)
+	}
+}
+
+func setupStackHandlers() {
+	testMux.HandleFunc("/changes/102", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"_number": 102, "status": "NEW"}`)
+	})
+	testMux.HandleFunc("/changes/102/revisions/current/related", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, relatedChangesJSON)
+	})
+}
+
+func TestChangesService_SubmitStack(t *testing.T) {
+	setup()
+	defer teardown()
+	setupStackHandlers()
+
+	var submitted []string
+	for _, n := range []string{"101", "102", "103"} {
+		n := n
+		testMux.HandleFunc("/changes/"+n+"/submit", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, "POST")
+			submitted = append(submitted, n)
+			if n == "102" {
+				http.Error(w, "submit requirement not fulfilled", http.StatusConflict)
+				return
+			}
+			fmt.Fprintf(w, `{"_number": %s, "status": "MERGED"}`, n)
+		})
+	}
+
+	results, err := testClient.Changes.SubmitStack(context.Background(), "102", nil)
+	if err == nil {
+		t.Fatal("Expected error")
+	}
+	if want := []string{"101", "102"}; !reflect.DeepEqual(submitted, want) {
+		t.Errorf("submitted = %v, want %v", submitted, want)
+	}
+	if len(results) != 2 || results[0].Err != nil || results[0].Change.Status != "MERGED" || results[1].Err == nil {
+		t.Errorf("Unexpected results %+v", results)
+	}
+}
+
+func TestChangesService_RebaseStack(t *testing.T) {
+	setup()
+	defer teardown()
+	setupStackHandlers()
+
+	var bases []string
+	for _, n := range []string{"102", "103"} {
+		n := n
+		testMux.HandleFunc("/changes/"+n+"/rebase", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, "POST")
+			var input gerrit.RebaseInput
+			if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+				t.Error(err)
+			}
+			if !input.AllowConflicts {
+				t.Error("AllowConflicts was not passed on")
+			}
+			bases = append(bases, n+"->"+input.Base)
+			fmt.Fprintf(w, `{"_number": %s}`, n)
+		})
+	}
+
+	results, err := testClient.Changes.RebaseStack(context.Background(), "102", &gerrit.RebaseInput{AllowConflicts: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"102->101", "103->102"}; !reflect.DeepEqual(bases, want) {
+		t.Errorf("rebases = %v, want %v", bases, want)
+	}
+	if len(results) != 2 || results[0].ChangeNumber != 102 || results[1].ChangeNumber != 103 {
+		t.Errorf("Unexpected results %+v", results)
+	}
+}
@@ -0,0 +1,133 @@
+package gerrit
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// GetSubmitPreview downloads the result of submitting a revision as archive in the given format,
+// e.g. ArchiveFormatTGZ.
+//
+// The archive contains a git bundle for every project that would be updated by the submission,
+// which includes all changes that are submitted together, e.g. due to a shared topic.
+// Use ExtractSubmitPreview to unpack the bundles.
+//
+// The archive is not buffered: the returned io.ReadCloser is the body of the response
+// and must be closed by the caller.
+//
+// Gerrit API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#preview-submit
+func (s *ChangesService) GetSubmitPreview(ctx context.Context, changeID, revisionID, format string) (io.ReadCloser, *Response, error) {
+	u := fmt.Sprintf("changes/%s/revisions/%s/preview_submit", changeID, revisionID)
+	return s.getArchive(ctx, u, format)
+}
+
+// SubmitPreviewBundle is the git bundle of a single project of a submit preview.
+type SubmitPreviewBundle struct {
+	Project string
+	// Path is the file the bundle was written to, or empty if it was not written.
+	// The commits can be fetched from it with "git fetch <path> <ref>".
+	Path string
+	// Refs are the refs that would be updated by the submission and the commits they would point to.
+	Refs []BundleRef
+	// Prerequisites are the commits the bundle is based on, which must exist in a repository to fetch from it.
+	Prerequisites []string
+}
+
+// BundleRef is a ref contained in a git bundle.
+type BundleRef struct {
+	Name   string
+	Commit string
+}
+
+// ErrInvalidBundle is returned by ExtractSubmitPreview if an archive entry is not a git bundle.
+var ErrInvalidBundle = errors.New("invalid git bundle")
+
+// ExtractSubmitPreview reads an archive as returned by GetSubmitPreview and lists the refs and commits of its bundles,
+// sorted by project.
+//
+// If dir is not empty, the bundles are also written to dir, which is created if needed.
+// The bundle of a project is written to "<dir>/<project>.bundle".
+func ExtractSubmitPreview(r io.Reader, format, dir string) ([]SubmitPreviewBundle, error) {
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+	}
+
+	var bundles []SubmitPreviewBundle
+	err := walkArchiveFiles(r, format, func(name string, r io.Reader) error {
+		b := SubmitPreviewBundle{Project: strings.TrimSuffix(name, ".git")}
+		if dir != "" {
+			target, err := archivePath(dir, b.Project+".bundle")
+			if err != nil {
+				return err
+			}
+			if err := writeArchiveFile(dir, target, r, 0o644); err != nil {
+				return err
+			}
+			f, err := os.Open(target)
+			if err != nil {
+				return err
+			}
+			defer f.Close() // nolint: errcheck
+			b.Path, r = target, f
+		}
+
+		var err error
+		b.Refs, b.Prerequisites, err = readBundleHeader(r)
+		if err != nil {
+			return fmt.Errorf("bundle of project %s: %w", b.Project, err)
+		}
+		bundles = append(bundles, b)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(bundles, func(i, j int) bool {
+		return bundles[i].Project < bundles[j].Project
+	})
+	return bundles, nil
+}
+
+// readBundleHeader reads the header of a git bundle in version 2 or 3.
+func readBundleHeader(r io.Reader) (refs []BundleRef, prerequisites []string, err error) {
+	br := bufio.NewReader(r)
+	signature, err := br.ReadString('\n')
+	if err != nil || (signature != "# v2 git bundle\n" && signature != "# v3 git bundle\n") {
+		return nil, nil, ErrInvalidBundle
+	}
+
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			return nil, nil, ErrInvalidBundle
+		}
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			return refs, prerequisites, nil
+		case strings.HasPrefix(line, "@"):
+			// Capabilities of version 3, like the object format.
+		case strings.HasPrefix(line, "-"):
+			fields := strings.Fields(line[1:])
+			if len(fields) == 0 {
+				return nil, nil, ErrInvalidBundle
+			}
+			prerequisites = append(prerequisites, fields[0])
+		default:
+			fields := strings.Fields(line)
+			if len(fields) != 2 {
+				return nil, nil, ErrInvalidBundle
+			}
+			refs = append(refs, BundleRef{Name: fields[1], Commit: fields[0]})
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package gerrit_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/andygrunwald/go-gerrit"
+)
+
+const (
+	testBundleV2 = "# v2 git bundle\n" +
+		"-1d3c2ab3b1d3c2ab3b1d3c2ab3b1d3c2ab3b1d3c Fix the build\n" +
+		"7a53d84c57a53d84c57a53d84c57a53d84c57a53 refs/heads/master\n" +
+		"\n" +
+		"PACK..."
+	testBundleV3 = "# v3 git bundle\n" +
+		"@object-format=sha1\n" +
+		"257cc5642257cc5642257cc5642257cc5642257c refs/heads/stable\n" +
+		"\n" +
+		"PACK..."
+)
+
+func TestChangesService_GetSubmitPreview(t *testing.T) {
+	setup()
+	defer teardown()
+
+	archive := buildTGZ(t, []archiveEntry{
+		{name: "tools", content: testBundleV3},
+		{name: "platform/build", content: testBundleV2},
+	})
+	testMux.HandleFunc("/changes/123/revisions/current/preview_submit", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testFormValues(t, r, testValues{"format": "tgz"})
+		w.Header().Set("Content-Type", "application/x-gzip")
+		w.Write(archive) // nolint: errcheck
+	})
+
+	rc, _, err := testClient.Changes.GetSubmitPreview(context.Background(), "123", "current", gerrit.ArchiveFormatTGZ)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+
+	dir := t.TempDir()
+	bundles, err := gerrit.ExtractSubmitPreview(rc, gerrit.ArchiveFormatTGZ, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []gerrit.SubmitPreviewBundle{
+		{
+			Project:       "platform/build",
+			Path:          filepath.Join(dir, "platform", "build.bundle"),
+			Refs:          []gerrit.BundleRef{{Name: "refs/heads/master", Commit: "7a53d84c57a53d84c57a53d84c57a53d84c57a53"}},
+			Prerequisites: []string{"1d3c2ab3b1d3c2ab3b1d3c2ab3b1d3c2ab3b1d3c"},
+		},
+		{
+			Project: "tools",
+			Path:    filepath.Join(dir, "tools.bundle"),
+			Refs:    []gerrit.BundleRef{{Name: "refs/heads/stable", Commit: "257cc5642257cc5642257cc5642257cc5642257c"}},
+		},
+	}
+	if !reflect.DeepEqual(bundles, want) {
+		t.Errorf("ExtractSubmitPreview():\ngot:  %+v\nwant: %+v", bundles, want)
+	}
+	for _, b := range bundles {
+		got, err := os.ReadFile(b.Path)
+		if err != nil {
+			t.Error(err)
+		} else if len(got) == 0 || string(got[len(got)-7:]) != "PACK..." {
+			t.Errorf("%s = %q", b.Path, got)
+		}
+	}
+}
+
+func TestExtractSubmitPreview_InvalidBundle(t *testing.T) {
+	archive := buildTGZ(t, []archiveEntry{{name: "tools", content: "not a bundle"}})
+	_, err := gerrit.ExtractSubmitPreview(bytes.NewReader(archive), gerrit.ArchiveFormatTGZ, "")
+	if !errors.Is(err, gerrit.ErrInvalidBundle) {
+		t.Errorf("ExtractSubmitPreview() error = %v, want %v", err, gerrit.ErrInvalidBundle)
+	}
+}
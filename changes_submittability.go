@@ -0,0 +1,254 @@
+package gerrit
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// SubmitBlockerKind classifies why a change can't be submitted.
+type SubmitBlockerKind string
+
+const (
+	// BlockerStatus means the change is not open.
+	BlockerStatus SubmitBlockerKind = "STATUS"
+	// BlockerWorkInProgress means the change is marked as work in progress.
+	BlockerWorkInProgress SubmitBlockerKind = "WORK_IN_PROGRESS"
+	// BlockerRequirement means a submit requirement is not satisfied for a reason
+	// that is not covered by a more specific kind.
+	BlockerRequirement SubmitBlockerKind = "REQUIREMENT"
+	// BlockerMissingLabel means a submit requirement needs a vote that is missing.
+	BlockerMissingLabel SubmitBlockerKind = "MISSING_LABEL"
+	// BlockerVeto means a submit requirement is blocked by a veto vote.
+	BlockerVeto SubmitBlockerKind = "VETO"
+	// BlockerUnresolvedComments means the change has unresolved comments.
+	BlockerUnresolvedComments SubmitBlockerKind = "UNRESOLVED_COMMENTS"
+	// BlockerMergeConflict means the change can't be merged into its destination branch.
+	BlockerMergeConflict SubmitBlockerKind = "MERGE_CONFLICT"
+	// BlockerParentChange means the change depends on a parent change that is not merged yet.
+	BlockerParentChange SubmitBlockerKind = "PARENT_CHANGE"
+)
+
+// SubmitBlocker is a single reason why a change can't be submitted.
+type SubmitBlocker struct {
+	Kind SubmitBlockerKind
+	// Requirement is the name of the unsatisfied submit requirement, if any.
+	Requirement string
+	// Label is the name of the missing or vetoed label, if any.
+	Label string
+	// Atoms are the atoms of the submittability expression the blocker was derived from:
+	// failing atoms, or passing atoms that are negated in the expression.
+	Atoms []string
+	// Accounts are the accounts that cast a veto vote.
+	Accounts []AccountInfo
+	// ParentChange is the number of the unmerged parent change.
+	ParentChange int
+	// Message is a human-readable explanation of the blocker.
+	Message string
+}
+
+// SubmitDiagnosis explains why a change can or can't be submitted.
+type SubmitDiagnosis struct {
+	// Submittable is true if no blocker was found.
+	Submittable bool
+	Blockers    []SubmitBlocker
+}
+
+// labelAtomPattern matches label atoms of submit requirement expressions,
+// e.g. "label:Code-Review=MAX" or "label:Code-Review=MIN,user=non_uploader".
+var labelAtomPattern = regexp.MustCompile(`^label[12]?:([^=<>,]+)`)
+
+// DiagnoseSubmit explains why change can't be submitted.
+//
+// The change should be retrieved with the SUBMIT_REQUIREMENTS, DETAILED_LABELS and
+// CURRENT_REVISION options, so that the submit requirements, the votes and the parents
+// of the current revision are available.
+// mergeable is the result of GetMergeable for the current revision and may be nil.
+//
+// The atoms of each unsatisfied submit requirement are interpreted. Gerrit reports the plain
+// atoms of the expression as failing or passing, so the expression is parsed to find out which
+// atoms are negated: plain atoms block the requirement if they fail, negated atoms if they pass.
+// Blocking label atoms are reported as missing or vetoed labels, a blocking "has:unresolved"
+// atom as unresolved comments.
+// Requirements that can't be interpreted are reported as BlockerRequirement.
+func DiagnoseSubmit(change *ChangeInfo, mergeable *MergeableInfo) *SubmitDiagnosis {
+	d := &SubmitDiagnosis{}
+	add := func(b SubmitBlocker) {
+		d.Blockers = append(d.Blockers, b)
+	}
+
+	if change.Status != "" && change.Status != "NEW" {
+		add(SubmitBlocker{Kind: BlockerStatus, Message: fmt.Sprintf("change is %s", strings.ToLower(change.Status))})
+	}
+	if change.WorkInProgress {
+		add(SubmitBlocker{Kind: BlockerWorkInProgress, Message: "change is work in progress"})
+	}
+
+	unresolvedReported := false
+	for _, r := range change.SubmitRequirements {
+		if r.Status != "UNSATISFIED" && r.Status != "ERROR" {
+			continue
+		}
+		expr := r.SubmittabilityExpressionResult
+		if r.Status == "ERROR" {
+			add(SubmitBlocker{Kind: BlockerRequirement, Requirement: r.Name, Message: fmt.Sprintf("%s: %s", r.Name, expr.ErrorMessage)})
+			continue
+		}
+
+		// A plain atom blocks the requirement if it fails, a negated atom if it passes.
+		negated := negatedAtoms(expr.Expression)
+		var blocking []string
+		for _, atom := range expr.FailingAtoms {
+			if !negated[atom] {
+				blocking = append(blocking, atom)
+			}
+		}
+		for _, atom := range expr.PassingAtoms {
+			if negated[atom] {
+				blocking = append(blocking, atom)
+			}
+		}
+		var other []string
+		reported := false
+		for _, atom := range blocking {
+			if m := labelAtomPattern.FindStringSubmatch(atom); m != nil {
+				add(labelBlocker(change, r.Name, m[1], atom, negated[atom]))
+				reported = true
+				continue
+			}
+			if negated[atom] && atom == "has:unresolved" {
+				add(SubmitBlocker{
+					Kind:        BlockerUnresolvedComments,
+					Requirement: r.Name,
+					Atoms:       []string{atom},
+					Message:     fmt.Sprintf("%s: %d unresolved comment(s)", r.Name, change.UnresolvedCommentCount),
+				})
+				unresolvedReported = true
+				reported = true
+				continue
+			}
+			other = append(other, atom)
+		}
+		if len(other) > 0 || !reported {
+			msg := fmt.Sprintf("%s: requirement is not satisfied", r.Name)
+			if expr.Expression != "" {
+				msg = fmt.Sprintf("%s: %q is not satisfied", r.Name, expr.Expression)
+			}
+			add(SubmitBlocker{Kind: BlockerRequirement, Requirement: r.Name, Atoms: other, Message: msg})
+		}
+	}
+	if !unresolvedReported && len(change.SubmitRequirements) == 0 && change.UnresolvedCommentCount > 0 {
+		add(SubmitBlocker{Kind: BlockerUnresolvedComments, Message: fmt.Sprintf("%d unresolved comment(s)", change.UnresolvedCommentCount)})
+	}
+
+	if (mergeable != nil && !mergeable.Mergeable) || change.ContainsGitConflicts {
+		add(SubmitBlocker{Kind: BlockerMergeConflict, Message: fmt.Sprintf("change has merge conflicts with %s", change.Branch)})
+	}
+
+	if rev, ok := change.Revisions[change.CurrentRevision]; ok {
+		for _, p := range rev.ParentsData {
+			if p.ChangeNumber > 0 && p.ChangeStatus != "MERGED" && !p.IsMergedInTargetBranch {
+				add(SubmitBlocker{
+					Kind:         BlockerParentChange,
+					ParentChange: p.ChangeNumber,
+					Message:      fmt.Sprintf("parent change %d is %s", p.ChangeNumber, strings.ToLower(p.ChangeStatus)),
+				})
+			}
+		}
+	}
+
+	d.Submittable = len(d.Blockers) == 0
+	return d
+}
+
+// labelBlocker creates the blocker for a failing label atom.
+// Negated atoms like "label:Code-Review=MIN" in "-label:Code-Review=MIN" block because somebody vetoed.
+func labelBlocker(change *ChangeInfo, requirement, label, atom string, negated bool) SubmitBlocker {
+	b := SubmitBlocker{
+		Kind:        BlockerMissingLabel,
+		Requirement: requirement,
+		Label:       label,
+		Atoms:       []string{atom},
+		Message:     fmt.Sprintf("%s: needs %s", requirement, strings.TrimPrefix(atom, "label:")),
+	}
+	if !negated {
+		return b
+	}
+
+	b.Kind = BlockerVeto
+	b.Message = fmt.Sprintf("%s: vetoed on %s", requirement, label)
+	if l, ok := change.Labels[label]; ok {
+		if s, err := l.Summarize(label); err == nil {
+			for _, a := range s.Blockers {
+				b.Accounts = append(b.Accounts, a.AccountInfo)
+			}
+		}
+	}
+	return b
+}
+
+// negatedAtoms returns the atoms of a submit requirement expression that are negated,
+// with "-" or NOT, either directly or by a negated group.
+// An atom that occurs both plain and negated is reported as negated.
+func negatedAtoms(expr string) map[string]bool {
+	negated := map[string]bool{}
+	groups := []bool{false}
+	pending := false
+	for _, tok := range expressionTokens(expr) {
+		switch tok {
+		case "AND", "OR":
+		case "NOT", "-":
+			pending = !pending
+		case "(":
+			groups = append(groups, groups[len(groups)-1] != pending)
+			pending = false
+		case ")":
+			if len(groups) > 1 {
+				groups = groups[:len(groups)-1]
+			}
+		default:
+			neg := groups[len(groups)-1] != pending
+			if strings.HasPrefix(tok, "-") {
+				tok, neg = tok[1:], !neg
+			}
+			if neg {
+				negated[tok] = true
+			}
+			pending = false
+		}
+	}
+	return negated
+}
+
+// expressionTokens splits a submit requirement expression into atoms, operators and parentheses.
+// A "-" directly before a parenthesis is returned as separate token.
+// Quoted values are kept within their atom.
+func expressionTokens(expr string) []string {
+	var tokens []string
+	var cur strings.Builder
+	flush := func() {
+		if cur.Len() > 0 {
+			tokens = append(tokens, cur.String())
+			cur.Reset()
+		}
+	}
+	quoted := false
+	for _, r := range expr {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			cur.WriteRune(r)
+		case quoted:
+			cur.WriteRune(r)
+		case r == '(' || r == ')':
+			flush()
+			tokens = append(tokens, string(r))
+		case r == ' ' || r == '\t' || r == '\n':
+			flush()
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	flush()
+	return tokens
+}
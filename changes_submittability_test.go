@@ -0,0 +1,131 @@
+package gerrit_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/andygrunwald/go-gerrit"
+)
+
+func TestDiagnoseSubmit(t *testing.T) {
+	const changeJSON = `{
+  "_number": 42,
+  "branch": "master",
+  "status": "NEW",
+  "work_in_progress": true,
+  "unresolved_comment_count": 2,
+  "current_revision": "abc",
+  "revisions": {
+    "abc": {
+      "_number": 2,
+      "parents_data": [
+        {"commit_id": "def", "change_number": 41, "patch_set_number": 1, "change_status": "NEW", "is_merged_in_target_branch": false}
+      ]
+    }
+  },
+  "labels": {
+    "Code-Review": {
+      "all": [{"value": -2, "_account_id": 1000096}],
+      "values": {"-2": "", "-1": "", " 0": "", "+1": "", "+2": ""}
+    }
+  },
+  "submit_requirements": [
+    {
+      "name": "Code-Review",
+      "status": "UNSATISFIED",
+      "is_legacy": false,
+      "submittability_expression_result": {
+        "expression": "label:Code-Review=MAX AND -label:Code-Review=MIN",
+        "fulfilled": false,
+        "status": "FAIL",
+        "passing_atoms": ["label:Code-Review=MIN"],
+        "failing_atoms": ["label:Code-Review=MAX"]
+      }
+    },
+    {
+      "name": "No-Unresolved-Comments",
+      "status": "UNSATISFIED",
+      "is_legacy": false,
+      "submittability_expression_result": {
+        "expression": "-has:unresolved",
+        "fulfilled": false,
+        "status": "FAIL",
+        "passing_atoms": ["has:unresolved"]
+      }
+    },
+    {
+      "name": "Verified",
+      "status": "SATISFIED",
+      "is_legacy": false,
+      "submittability_expression_result": {"expression": "label:Verified=MAX", "fulfilled": true, "status": "PASS", "passing_atoms": ["label:Verified=MAX"]}
+    },
+    {
+      "name": "Custom",
+      "status": "UNSATISFIED",
+      "is_legacy": false,
+      "submittability_expression_result": {
+        "expression": "is:true AND hasfooter:Bug AND NOT (file:secret.txt OR label:Verified=MIN)",
+        "fulfilled": false,
+        "status": "FAIL",
+        "passing_atoms": ["is:true"],
+        "failing_atoms": ["hasfooter:Bug", "file:secret.txt", "label:Verified=MIN"]
+      }
+    }
+  ]
+}`
+	var change gerrit.ChangeInfo
+	if err := json.Unmarshal([]byte(changeJSON), &change); err != nil {
+		t.Fatal(err)
+	}
+
+	d := gerrit.DiagnoseSubmit(&change, &gerrit.MergeableInfo{Mergeable: false})
+	if d.Submittable {
+		t.Error("Submittable = true, want false")
+	}
+
+	var kinds []gerrit.SubmitBlockerKind
+	for _, b := range d.Blockers {
+		kinds = append(kinds, b.Kind)
+	}
+	want := []gerrit.SubmitBlockerKind{
+		gerrit.BlockerWorkInProgress,
+		gerrit.BlockerMissingLabel,
+		gerrit.BlockerVeto,
+		gerrit.BlockerUnresolvedComments,
+		gerrit.BlockerRequirement,
+		gerrit.BlockerMergeConflict,
+		gerrit.BlockerParentChange,
+	}
+	if !reflect.DeepEqual(kinds, want) {
+		t.Fatalf("blocker kinds:\ngot:  %v\nwant: %v", kinds, want)
+	}
+
+	if b := d.Blockers[1]; b.Label != "Code-Review" || b.Requirement != "Code-Review" {
+		t.Errorf("Unexpected missing label blocker %+v", b)
+	}
+	if b := d.Blockers[2]; len(b.Accounts) != 1 || b.Accounts[0].AccountID != 1000096 || !reflect.DeepEqual(b.Atoms, []string{"label:Code-Review=MIN"}) {
+		t.Errorf("Unexpected veto blocker %+v", b)
+	}
+	if b := d.Blockers[3]; b.Requirement != "No-Unresolved-Comments" || !reflect.DeepEqual(b.Atoms, []string{"has:unresolved"}) {
+		t.Errorf("Unexpected unresolved comments blocker %+v", b)
+	}
+	if b := d.Blockers[4]; b.Requirement != "Custom" || !reflect.DeepEqual(b.Atoms, []string{"hasfooter:Bug"}) {
+		t.Errorf("Unexpected requirement blocker %+v", b)
+	}
+	if b := d.Blockers[6]; b.ParentChange != 41 {
+		t.Errorf("Unexpected parent blocker %+v", b)
+	}
+}
+
+func TestDiagnoseSubmit_Submittable(t *testing.T) {
+	change := &gerrit.ChangeInfo{
+		Status: "NEW",
+		SubmitRequirements: []gerrit.SubmitRequirementResultInfo{
+			{Name: "Code-Review", Status: "SATISFIED"},
+		},
+	}
+	if d := gerrit.DiagnoseSubmit(change, &gerrit.MergeableInfo{Mergeable: true}); !d.Submittable || len(d.Blockers) != 0 {
+		t.Errorf("Unexpected diagnosis %+v", d)
+	}
+}
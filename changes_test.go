@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 
 	"github.com/andygrunwald/go-gerrit"
@@ -355,6 +356,39 @@ func TestChangesService_RebaseChange_Conflict(t *testing.T) {
 	}
 }
 
+func TestChangesService_RebaseChain(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/changes/123/rebase:chain" {
+			t.Errorf("%s != /changes/123/rebase:chain", r.URL.Path)
+		}
+		var input gerrit.RebaseInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			t.Error(err)
+		}
+		if !input.AllowConflicts || !input.OnBehalfOfUploader {
+			t.Errorf("Unexpected input %+v", input)
+		}
+		_, err := fmt.Fprint(w, `)]}'`+"\n"+`{"rebased_changes": [{"_number": 122}, {"_number": 123}], "contains_git_conflicts": true}`)
+		if err != nil {
+			t.Error(err)
+		}
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	client, err := gerrit.NewClient(ctx, ts.URL, nil)
+	if err != nil {
+		t.Error(err)
+	}
+	info, _, err := client.Changes.RebaseChain(ctx, "123", &gerrit.RebaseInput{AllowConflicts: true, OnBehalfOfUploader: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(info.RebasedChanges) != 2 || info.RebasedChanges[1].Number != 123 || !info.ContainsGitConflicts {
+		t.Errorf("Unexpected result %+v", info)
+	}
+}
+
 func TestChangesService_RestoreChange(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/changes/123/restore" {
@@ -441,6 +475,39 @@ func TestChangesService_RevertChange_Conflict(t *testing.T) {
 	}
 }
 
+func TestChangesService_RevertSubmission(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/changes/123/revert_submission" {
+			t.Errorf("%s != /changes/123/revert_submission", r.URL.Path)
+		}
+		var input gerrit.RevertInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			t.Error(err)
+		}
+		if input.Topic != "revert-topic" {
+			t.Errorf("Unexpected input %+v", input)
+		}
+		_, err := fmt.Fprint(w, `)]}'`+"\n"+`{"revert_changes": [{"project": "a", "_number": 201}, {"project": "b", "_number": 202}]}`)
+		if err != nil {
+			t.Error(err)
+		}
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	client, err := gerrit.NewClient(ctx, ts.URL, nil)
+	if err != nil {
+		t.Error(err)
+	}
+	info, _, err := client.Changes.RevertSubmission(ctx, "123", &gerrit.RevertInput{Topic: "revert-topic"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(info.RevertChanges) != 2 || info.RevertChanges[1].Project != "b" {
+		t.Errorf("Unexpected result %+v", info)
+	}
+}
+
 func TestChangesService_SetCommitMessage(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/changes/123/message" {
@@ -542,3 +609,93 @@ func TestChangesService_SetReadyForReview_NotFound(t *testing.T) {
 		t.Error("Expected 404 code")
 	}
 }
+
+func TestChangesService_SetWorkInProgress(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/changes/123/wip" {
+			t.Errorf("%s != /changes/123/wip", r.URL.Path)
+		}
+		if r.Method != "POST" {
+			t.Error("Method != POST")
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	client, err := gerrit.NewClient(ctx, ts.URL, nil)
+	if err != nil {
+		t.Error(err)
+	}
+	_, err = client.Changes.SetWorkInProgress(ctx, "123", &gerrit.WorkInProgressInput{Message: "Waiting for CI"})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestChangesService_MarkPrivate(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/changes/123/private" {
+			t.Errorf("%s != /changes/123/private", r.URL.Path)
+		}
+		switch r.Method {
+		case "POST":
+			w.WriteHeader(http.StatusCreated)
+		case "DELETE":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("Unexpected method %s", r.Method)
+		}
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	client, err := gerrit.NewClient(ctx, ts.URL, nil)
+	if err != nil {
+		t.Error(err)
+	}
+	resp, err := client.Changes.MarkPrivate(ctx, "123", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	if resp.StatusCode != http.StatusCreated {
+		t.Error("Expected 201 code")
+	}
+	_, err = client.Changes.UnmarkPrivate(ctx, "123", &gerrit.PrivateInput{Message: "Ready to share"})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestChangesService_IgnoreAndReviewedFlags(t *testing.T) {
+	var paths []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" {
+			t.Error("Method != PUT")
+		}
+		paths = append(paths, r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	client, err := gerrit.NewClient(ctx, ts.URL, nil)
+	if err != nil {
+		t.Error(err)
+	}
+	for _, f := range []func(context.Context, string) (*gerrit.Response, error){
+		client.Changes.IgnoreChange,
+		client.Changes.UnignoreChange,
+		client.Changes.MarkAsReviewed,
+		client.Changes.MarkAsUnreviewed,
+	} {
+		if _, err := f(ctx, "123"); err != nil {
+			t.Error(err)
+		}
+	}
+
+	want := []string{"/changes/123/ignore", "/changes/123/unignore", "/changes/123/reviewed", "/changes/123/unreviewed"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("paths:\ngot:  %v\nwant: %v", paths, want)
+	}
+}
@@ -0,0 +1,344 @@
+package gerrit
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Common footer keys used by Gerrit and git.
+const (
+	FooterChangeID         = "Change-Id"
+	FooterSignedOffBy      = "Signed-off-by"
+	FooterBug              = "Bug"
+	FooterReviewedOn       = "Reviewed-on"
+	FooterCherryPickedFrom = "Cherry-picked-from"
+)
+
+var (
+	// ErrMissingChangeID is returned by CommitMessage.Validate if the message has no Change-Id footer.
+	ErrMissingChangeID = errors.New("missing Change-Id in message footer")
+
+	// ErrMultipleChangeIDs is returned by CommitMessage.Validate if the message has more than one Change-Id footer.
+	ErrMultipleChangeIDs = errors.New("multiple Change-Id lines in message footer")
+
+	// ErrInvalidChangeID is returned by CommitMessage.Validate and CommitMessage.SetChangeID
+	// if the Change-Id is not of the form "I<sha1>".
+	ErrInvalidChangeID = errors.New("invalid Change-Id line format in message footer")
+
+	// ErrChangeIDNotInFooter is returned by CommitMessage.Validate if a Change-Id line
+	// was found in the message body instead of the footer.
+	ErrChangeIDNotInFooter = errors.New("Change-Id must be in message footer") // nolint: stylecheck
+
+	// ErrEmptySubject is returned by CommitMessage.Validate if the message has no subject.
+	ErrEmptySubject = errors.New("commit message subject is empty")
+)
+
+// footerPattern matches a git trailer line like "Signed-off-by: Jane <jane@example.com>".
+var footerPattern = regexp.MustCompile(`^([A-Za-z0-9-]+)[ \t]*:[ \t]*(.*)$`)
+
+// Footer is a single footer (git trailer) of a commit message.
+type Footer struct {
+	// Key of the footer, e.g. "Change-Id".
+	// Lines of the footer paragraph that are not footers are kept verbatim
+	// in Value with an empty Key.
+	Key string
+	// Value of the footer.
+	// Continuation lines are kept in Value, separated by a newline and with their leading whitespace.
+	Value string
+	// Raw is the footer as it appeared in the parsed commit message, including continuation lines.
+	// It is empty for footers that were not parsed.
+	Raw string
+}
+
+// String returns the footer line as it appears in the commit message.
+// A parsed footer is returned verbatim as long as its Key and Value were not changed,
+// otherwise it is formatted as "Key: Value".
+func (f Footer) String() string {
+	if f.Raw != "" {
+		if parsed := parseFooterLines(strings.Split(f.Raw, "\n")); parsed.Key == f.Key && parsed.Value == f.Value {
+			return f.Raw
+		}
+	}
+	if f.Key == "" {
+		return f.Value
+	}
+	return f.Key + ": " + f.Value
+}
+
+// parseFooterLines parses a footer line followed by its continuation lines.
+func parseFooterLines(lines []string) Footer {
+	raw := strings.Join(lines, "\n")
+	sm := footerPattern.FindStringSubmatch(lines[0])
+	if sm == nil || isURLFooter(sm) {
+		return Footer{Value: raw, Raw: raw}
+	}
+	f := Footer{Key: sm[1], Value: sm[2], Raw: raw}
+	for _, line := range lines[1:] {
+		f.Value += "\n" + line
+	}
+	return f
+}
+
+// isURLFooter reports whether a match of footerPattern is a URL like "http://example.com"
+// rather than a footer.
+func isURLFooter(sm []string) bool {
+	return strings.HasPrefix(sm[2], "//")
+}
+
+// CommitMessage is a commit message split into its body and its footers.
+// It can be used to inspect and edit the footers of CommitInfo.Message before
+// sending the result back with ChangesService.SetCommitMessage or
+// ChangesService.ChangeCommitMessageInChangeEdit.
+//
+//	msg := gerrit.ParseCommitMessage(commit.Message)
+//	msg.AddFooter(gerrit.FooterBug, "1234")
+//	_, err := client.Changes.SetCommitMessage(ctx, changeID, &gerrit.CommitMessageInput{Message: msg.String()})
+type CommitMessage struct {
+	// Body contains the subject and the body of the message, without the footers.
+	Body string
+	// Footers are the footers of the message in order of appearance.
+	Footers []Footer
+}
+
+// ParseCommitMessage splits message into body and footers.
+//
+// The footers follow git's trailer rules: only the last paragraph of the message
+// is considered, and only if it is not the subject paragraph.
+// The paragraph is a footer block if all of its lines are footers or continuation lines,
+// or if at least 25% of them are footers and one of them is a Signed-off-by or
+// "(cherry picked from commit ...)" line.
+func ParseCommitMessage(message string) *CommitMessage {
+	message = strings.Replace(message, "\r\n", "\n", -1)
+	lines := strings.Split(strings.TrimRight(message, "\n"), "\n")
+
+	start := len(lines)
+	for start > 0 && strings.TrimSpace(lines[start-1]) != "" {
+		start--
+	}
+
+	m := &CommitMessage{Body: strings.Join(lines, "\n")}
+	if start == 0 {
+		return m
+	}
+	if footers, ok := parseFooters(lines[start:]); ok {
+		m.Body = strings.TrimRight(strings.Join(lines[:start], "\n"), "\n")
+		m.Footers = footers
+	}
+	return m
+}
+
+// parseFooters parses the lines of the last paragraph and reports whether they form a footer block.
+func parseFooters(lines []string) ([]Footer, bool) {
+	// Group the lines into footers and their continuation lines.
+	var groups [][]string
+	isFooter := func(line string) bool {
+		sm := footerPattern.FindStringSubmatch(line)
+		return sm != nil && !isURLFooter(sm)
+	}
+	for _, line := range lines {
+		n := len(groups)
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && n > 0 && isFooter(groups[n-1][0]) {
+			groups[n-1] = append(groups[n-1], line)
+			continue
+		}
+		groups = append(groups, []string{line})
+	}
+
+	var footers []Footer
+	matched, other := 0, 0
+	gitGenerated := false
+	for _, group := range groups {
+		f := parseFooterLines(group)
+		footers = append(footers, f)
+		if f.Key != "" {
+			matched++
+			if strings.EqualFold(f.Key, FooterSignedOffBy) {
+				gitGenerated = true
+			}
+			continue
+		}
+		if strings.HasPrefix(f.Value, "(cherry picked from commit ") {
+			gitGenerated = true
+		}
+		other++
+	}
+
+	if matched == 0 {
+		return nil, false
+	}
+	if other > 0 && (!gitGenerated || matched*4 < matched+other) {
+		return nil, false
+	}
+	return footers, true
+}
+
+// String returns the full commit message, terminated by a newline.
+func (m *CommitMessage) String() string {
+	var b strings.Builder
+	b.WriteString(m.Body)
+	if len(m.Footers) > 0 {
+		if m.Body != "" {
+			b.WriteString("\n\n")
+		}
+		for i, f := range m.Footers {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			b.WriteString(f.String())
+		}
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// Subject returns the first line of the message.
+func (m *CommitMessage) Subject() string {
+	return strings.SplitN(m.Body, "\n", 2)[0]
+}
+
+// Footer returns the values of all footers with the given key.
+// Keys are compared case-insensitively.
+func (m *CommitMessage) Footer(key string) []string {
+	var values []string
+	for _, f := range m.Footers {
+		if f.Key != "" && strings.EqualFold(f.Key, key) {
+			values = append(values, f.Value)
+		}
+	}
+	return values
+}
+
+// ChangeID returns the value of the first Change-Id footer or an empty string.
+func (m *CommitMessage) ChangeID() string {
+	if ids := m.Footer(FooterChangeID); len(ids) > 0 {
+		return ids[0]
+	}
+	return ""
+}
+
+// AddFooter appends a footer to the end of the footer block.
+func (m *CommitMessage) AddFooter(key, value string) {
+	m.Footers = append(m.Footers, Footer{Key: key, Value: value})
+}
+
+// SetFooter replaces the value of the first footer with the given key
+// and removes all other footers with this key.
+// If no footer with this key exists, it is appended.
+func (m *CommitMessage) SetFooter(key, value string) {
+	found := false
+	footers := m.Footers[:0]
+	for _, f := range m.Footers {
+		if f.Key != "" && strings.EqualFold(f.Key, key) {
+			if found {
+				continue
+			}
+			f.Value = value
+			found = true
+		}
+		footers = append(footers, f)
+	}
+	m.Footers = footers
+	if !found {
+		m.AddFooter(key, value)
+	}
+}
+
+// RemoveFooter removes all footers with the given key.
+// The Change-Id footer is kept, use SetChangeID to replace it.
+func (m *CommitMessage) RemoveFooter(key string) {
+	if strings.EqualFold(key, FooterChangeID) {
+		return
+	}
+	footers := m.Footers[:0]
+	for _, f := range m.Footers {
+		if f.Key != "" && strings.EqualFold(f.Key, key) {
+			continue
+		}
+		footers = append(footers, f)
+	}
+	m.Footers = footers
+}
+
+// SetChangeID sets the Change-Id footer.
+// An existing Change-Id footer is replaced in place.
+// Otherwise the footer is inserted before the first Signed-off-by footer,
+// like the commit-msg hook does, or appended to the footer block.
+func (m *CommitMessage) SetChangeID(changeID string) error {
+	if !changeIDPattern.MatchString(changeID) {
+		return ErrInvalidChangeID
+	}
+	if m.ChangeID() != "" {
+		m.SetFooter(FooterChangeID, changeID)
+		return nil
+	}
+
+	f := Footer{Key: FooterChangeID, Value: changeID}
+	for i := range m.Footers {
+		if strings.EqualFold(m.Footers[i].Key, FooterSignedOffBy) {
+			m.Footers = append(m.Footers[:i], append([]Footer{f}, m.Footers[i:]...)...)
+			return nil
+		}
+	}
+	m.Footers = append(m.Footers, f)
+	return nil
+}
+
+// EnsureChangeID adds a generated Change-Id footer if the message has none
+// and returns the Change-Id of the message.
+func (m *CommitMessage) EnsureChangeID() string {
+	if id := m.ChangeID(); id != "" {
+		return id
+	}
+	id := GenerateChangeID(m.String())
+	m.SetChangeID(id) // nolint: errcheck
+	return id
+}
+
+// Validate checks the message the same way Gerrit does on upload:
+// the message needs a subject and exactly one well-formed Change-Id in its footer.
+func (m *CommitMessage) Validate() error {
+	if strings.TrimSpace(m.Subject()) == "" {
+		return ErrEmptySubject
+	}
+
+	ids := m.Footer(FooterChangeID)
+	switch {
+	case len(ids) > 1:
+		return ErrMultipleChangeIDs
+	case len(ids) == 1:
+		if !changeIDPattern.MatchString(strings.TrimSpace(ids[0])) {
+			return ErrInvalidChangeID
+		}
+		return nil
+	}
+
+	for _, line := range strings.Split(m.Body, "\n") {
+		if sm := footerPattern.FindStringSubmatch(line); sm != nil && strings.EqualFold(sm[1], FooterChangeID) {
+			return ErrChangeIDNotInFooter
+		}
+	}
+	return ErrMissingChangeID
+}
+
+// GenerateChangeID generates a new Change-Id for message.
+//
+// Like the commit-msg hook, the Change-Id is the SHA-1 git blob hash of
+// the user and host name, the current time, the message and some random data,
+// prefixed with "I".
+func GenerateChangeID(message string) string {
+	user := os.Getenv("USER")
+	host, _ := os.Hostname()
+	random := make([]byte, 16)
+	rand.Read(random) // nolint: errcheck
+
+	content := fmt.Sprintf("%s\n%s\n%s\n%s\n%x\n", user, host, time.Now().Format(time.UnixDate), message, random)
+	h := sha1.New()
+	fmt.Fprintf(h, "blob %d\x00%s", len(content), content)
+	return fmt.Sprintf("I%x", h.Sum(nil))
+}
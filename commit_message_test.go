@@ -0,0 +1,154 @@
+package gerrit_test
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/andygrunwald/go-gerrit"
+)
+
+func TestParseCommitMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		body    string
+		footers []gerrit.Footer
+	}{
+		{
+			name:    "subject only",
+			message: "Fix the thing\n",
+			body:    "Fix the thing",
+		},
+		{
+			name:    "footers",
+			message: "Fix the thing\n\nLonger description.\n\nBug: 123\nChange-Id: I8473b95934b5732ac55d26311a706c9c2bde9940\n",
+			body:    "Fix the thing\n\nLonger description.",
+			footers: []gerrit.Footer{
+				{Key: "Bug", Value: "123", Raw: "Bug: 123"},
+				{Key: "Change-Id", Value: "I8473b95934b5732ac55d26311a706c9c2bde9940", Raw: "Change-Id: I8473b95934b5732ac55d26311a706c9c2bde9940"},
+			},
+		},
+		{
+			name:    "subject paragraph is never a footer",
+			message: "Bug: 123\n",
+			body:    "Bug: 123",
+		},
+		{
+			name:    "continuation lines",
+			message: "Subject\n\nTested-by: a very long\n  continued line\nChange-Id: I8473b95934b5732ac55d26311a706c9c2bde9940\n",
+			body:    "Subject",
+			footers: []gerrit.Footer{
+				{Key: "Tested-by", Value: "a very long\n  continued line", Raw: "Tested-by: a very long\n  continued line"},
+				{Key: "Change-Id", Value: "I8473b95934b5732ac55d26311a706c9c2bde9940", Raw: "Change-Id: I8473b95934b5732ac55d26311a706c9c2bde9940"},
+			},
+		},
+		{
+			name:    "last paragraph is prose",
+			message: "Subject\n\nThis is: not a footer block\nbecause of this line\n",
+			body:    "Subject\n\nThis is: not a footer block\nbecause of this line",
+		},
+		{
+			name:    "git generated trailers",
+			message: "Subject\n\n(cherry picked from commit 0123456789abcdef0123456789abcdef01234567)\nSigned-off-by: Jane <jane@example.com>\nChange-Id: I8473b95934b5732ac55d26311a706c9c2bde9940\n",
+			body:    "Subject",
+			footers: []gerrit.Footer{
+				{Value: "(cherry picked from commit 0123456789abcdef0123456789abcdef01234567)", Raw: "(cherry picked from commit 0123456789abcdef0123456789abcdef01234567)"},
+				{Key: "Signed-off-by", Value: "Jane <jane@example.com>", Raw: "Signed-off-by: Jane <jane@example.com>"},
+				{Key: "Change-Id", Value: "I8473b95934b5732ac55d26311a706c9c2bde9940", Raw: "Change-Id: I8473b95934b5732ac55d26311a706c9c2bde9940"},
+			},
+		},
+		{
+			name:    "unusual spacing",
+			message: "Subject\n\nBug:123\nChange-Id :  I8473b95934b5732ac55d26311a706c9c2bde9940\n",
+			body:    "Subject",
+			footers: []gerrit.Footer{
+				{Key: "Bug", Value: "123", Raw: "Bug:123"},
+				{Key: "Change-Id", Value: "I8473b95934b5732ac55d26311a706c9c2bde9940", Raw: "Change-Id :  I8473b95934b5732ac55d26311a706c9c2bde9940"},
+			},
+		},
+		{
+			name:    "URL line",
+			message: "Subject\n\nhttp://example.com is where\nSigned-off-by: Jane <jane@example.com>\n",
+			body:    "Subject",
+			footers: []gerrit.Footer{
+				{Value: "http://example.com is where", Raw: "http://example.com is where"},
+				{Key: "Signed-off-by", Value: "Jane <jane@example.com>", Raw: "Signed-off-by: Jane <jane@example.com>"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := gerrit.ParseCommitMessage(tt.message)
+			if m.Body != tt.body {
+				t.Errorf("Body:\ngot:  %q\nwant: %q", m.Body, tt.body)
+			}
+			if !reflect.DeepEqual(m.Footers, tt.footers) {
+				t.Errorf("Footers:\ngot:  %+v\nwant: %+v", m.Footers, tt.footers)
+			}
+			if got := m.String(); got != tt.message {
+				t.Errorf("String():\ngot:  %q\nwant: %q", got, tt.message)
+			}
+		})
+	}
+}
+
+func TestCommitMessage_EditFooters(t *testing.T) {
+	m := gerrit.ParseCommitMessage("Subject\n\nBody\n\nBug:1\nBug: 2\nTested-by:Joe\nSigned-off-by: Jane <jane@example.com>\n")
+
+	if err := m.SetChangeID("I8473b95934b5732ac55d26311a706c9c2bde9940"); err != nil {
+		t.Fatal(err)
+	}
+	m.SetFooter("bug", "3")
+	m.AddFooter(gerrit.FooterReviewedOn, "https://review.example.com/c/project/+/42")
+	m.RemoveFooter(gerrit.FooterSignedOffBy)
+	m.RemoveFooter(gerrit.FooterChangeID)
+
+	want := "Subject\n\nBody\n\nBug: 3\nTested-by:Joe\nChange-Id: I8473b95934b5732ac55d26311a706c9c2bde9940\nReviewed-on: https://review.example.com/c/project/+/42\n"
+	if got := m.String(); got != want {
+		t.Errorf("String():\ngot:  %q\nwant: %q", got, want)
+	}
+	if err := m.Validate(); err != nil {
+		t.Errorf("Validate(): %v", err)
+	}
+
+	if err := m.SetChangeID("Ideadbeef"); err != gerrit.ErrInvalidChangeID {
+		t.Errorf("SetChangeID with invalid ID: got %v, want %v", err, gerrit.ErrInvalidChangeID)
+	}
+}
+
+func TestCommitMessage_Validate(t *testing.T) {
+	tests := []struct {
+		message string
+		want    error
+	}{
+		{"Subject\n\nChange-Id: I8473b95934b5732ac55d26311a706c9c2bde9940\n", nil},
+		{"\n", gerrit.ErrEmptySubject},
+		{"Subject\n", gerrit.ErrMissingChangeID},
+		{"Subject\n\nChange-Id: I8473b95934b5732ac55d26311a706c9c2bde9940\nSome text\n", gerrit.ErrChangeIDNotInFooter},
+		{"Subject\n\nChange-Id: I1\nChange-Id: I2\n", gerrit.ErrMultipleChangeIDs},
+		{"Subject\n\nChange-Id: 8473b95934b5732ac55d26311a706c9c2bde9940\n", gerrit.ErrInvalidChangeID},
+	}
+	for _, tt := range tests {
+		if got := gerrit.ParseCommitMessage(tt.message).Validate(); got != tt.want {
+			t.Errorf("Validate(%q) = %v, want %v", tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestCommitMessage_EnsureChangeID(t *testing.T) {
+	m := gerrit.ParseCommitMessage("Subject\n\nSigned-off-by: Jane <jane@example.com>\n")
+	id := m.EnsureChangeID()
+	if !regexp.MustCompile(`^I[0-9a-f]{40}$`).MatchString(id) {
+		t.Fatalf("EnsureChangeID() = %q, not a valid Change-Id", id)
+	}
+	if got := m.Footers[0]; got.Key != gerrit.FooterChangeID || got.Value != id {
+		t.Errorf("Change-Id not inserted before Signed-off-by: %+v", m.Footers)
+	}
+	if again := m.EnsureChangeID(); again != id {
+		t.Errorf("EnsureChangeID() changed existing Change-Id: %q != %q", again, id)
+	}
+	if other := gerrit.GenerateChangeID(m.String()); other == id {
+		t.Errorf("GenerateChangeID() returned the same ID twice: %q", other)
+	}
+}